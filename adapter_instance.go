@@ -0,0 +1,278 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AdapterInstance is an instance of an adapter, i.e. the configuration
+// for collecting data from a particular source, e.g. a vCenter Server.
+type AdapterInstance struct {
+	// Identifier of the AdapterInstance.
+	ID string `json:"id,omitempty"`
+	// Resource key of the AdapterInstance.
+	Key *ResourceKey `json:"resourceKey,omitempty"`
+	// Description of the AdapterInstance.
+	Description string `json:"description,omitempty"`
+	// Identifier of the collector and the collector group running
+	// the AdapterInstance.
+	CollectorID      int    `json:"collectorId,omitempty"`
+	CollectorGroupID string `json:"collectorGroupId,omitempty"`
+	// Identifier of the credential the AdapterInstance connects to its
+	// data source with.
+	CredentialID string `json:"credentialInstanceId,omitempty"`
+	// The collection interval in minutes.
+	MonitoringInterval int `json:"monitoringInterval,omitempty"`
+	// The number of metrics and resources collected.
+	MetricsCollected   int `json:"numberOfMetricsCollected,omitempty"`
+	ResourcesCollected int `json:"numberOfResourcesCollected,omitempty"`
+	// The time of the last heartbeat and the last collection.
+	LastHeartbeat Timestamp `json:"lastHeartbeat"`
+	LastCollected Timestamp `json:"lastCollected"`
+	// The message reported by the AdapterInstance.
+	Message string `json:"messageFromAdapterInstance,omitempty"`
+	// The resource state and collection status of the AdapterInstance,
+	// e.g. STARTED and DATA_RECEIVING.
+	State  ResourceState  `json:"resourceState,omitempty"`
+	Status ResourceStatus `json:"resourceStatus,omitempty"`
+	// Set of useful links related to the current object.
+	Links []*Link `json:"links,omitempty"`
+}
+
+// AdapterInstancesResponse is a response with adapter instances.
+type AdapterInstancesResponse struct {
+	AdapterInstances []*AdapterInstance `json:"adapterInstancesInfoDto,omitempty"`
+}
+
+// UnmarshalJSON unpacks byte array into AdapterInstance.
+func (a *AdapterInstance) UnmarshalJSON(b []byte) error {
+	type alias AdapterInstance
+	aux := &struct {
+		*alias
+		Key interface{} `json:"resourceKey,omitempty"`
+	}{
+		alias: (*alias)(a),
+	}
+	if err := json.Unmarshal(b, aux); err != nil {
+		return fmt.Errorf("failed to unpack AdapterInstance: %s", err)
+	}
+	if aux.Key != nil {
+		// The other fields of AdapterInstance tolerate unknown keys,
+		// and so does its resource key.
+		key, err := unpackResourceKey(aux.Key, "resourceKey", &decoder{lenient: true})
+		if err != nil {
+			return fmt.Errorf("failed to unpack AdapterInstance: %s", err)
+		}
+		a.Key = key
+	}
+	return nil
+}
+
+// Name returns the name of the adapter instance.
+func (a *AdapterInstance) Name() string {
+	if a.Key == nil {
+		return ""
+	}
+	return a.Key.Name
+}
+
+// GetAdapterInstances returns a list of adapter instances of the provided
+// adapter kind, e.g. VMWARE. When the kind is empty, all adapter instances
+// are returned.
+func (c *Client) GetAdapterInstances(adapterKind string) ([]*AdapterInstance, error) {
+	instances := []*AdapterInstance{}
+	if err := c.authenticate(); err != nil {
+		return instances, err
+	}
+	params := make(map[string]string)
+	if adapterKind != "" {
+		params["adapterKindKey"] = adapterKind
+	}
+	b, err := c.request("GET", "adapters", params)
+	if err != nil {
+		return instances, err
+	}
+	resp := &AdapterInstancesResponse{}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return instances, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	instances = append(instances, resp.AdapterInstances...)
+
+	// The status of adapter instances is reported by their resources.
+	var ids []string
+	for _, instance := range instances {
+		ids = append(ids, instance.ID)
+		c.cacheAdapterInstanceName(instance.ID, instance.Name())
+	}
+	if len(ids) == 0 {
+		return instances, nil
+	}
+	resources, err := c.getResourcesByID(ids)
+	if err != nil {
+		return instances, err
+	}
+	states := make(map[string]*ResourceStatusState)
+	for _, r := range resources {
+		for _, s := range r.StatusStates {
+			if s.AdapterInstanceID == r.ID {
+				states[r.ID] = s
+			}
+		}
+	}
+	for _, instance := range instances {
+		if s, exists := states[instance.ID]; exists {
+			instance.State = s.State
+			instance.Status = s.Status
+		}
+	}
+	return instances, nil
+}
+
+// GetAdapterInstance returns the adapter instance with the provided identifier.
+func (c *Client) GetAdapterInstance(id string) (*AdapterInstance, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty adapter instance id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.request("GET", "adapters/"+id, params)
+	if err != nil {
+		return nil, err
+	}
+	instance := &AdapterInstance{}
+	if err := json.Unmarshal(b, instance); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	c.cacheAdapterInstanceName(instance.ID, instance.Name())
+	return instance, nil
+}
+
+// StartAdapterInstance starts the collection of data by the adapter
+// instance with the provided identifier.
+func (c *Client) StartAdapterInstance(id string) error {
+	return c.setAdapterInstanceMonitoringState(id, "start")
+}
+
+// StopAdapterInstance stops the collection of data by the adapter
+// instance with the provided identifier.
+func (c *Client) StopAdapterInstance(id string) error {
+	return c.setAdapterInstanceMonitoringState(id, "stop")
+}
+
+func (c *Client) setAdapterInstanceMonitoringState(id, state string) error {
+	if id == "" {
+		return fmt.Errorf("empty adapter instance id")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	if _, err := c.request("PUT", "adapters/"+id+"/monitoringstate/"+state, params); err != nil {
+		return fmt.Errorf("failed to %s adapter instance %s: %s", state, id, err)
+	}
+	return nil
+}
+
+// TestAdapterInstanceConnection tests the connection of the adapter
+// instance with the provided identifier to its data source.
+func (c *Client) TestAdapterInstanceConnection(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty adapter instance id")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	instance, err := c.GetAdapterInstance(id)
+	if err != nil {
+		return err
+	}
+	payload, err := instance.connectionPayload()
+	if err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	if _, err := c.requestWithPayload("POST", "adapters/testConnection", params, payload); err != nil {
+		return fmt.Errorf("connection test of adapter instance %s failed: %s", id, err)
+	}
+	return nil
+}
+
+// connectionPayload returns AdapterInstance in the format accepted by the
+// connection test, i.e. with the reference to its credential.
+func (a *AdapterInstance) connectionPayload() (map[string]interface{}, error) {
+	if a.Key == nil {
+		return nil, fmt.Errorf("adapter instance %s has no resource key", a.ID)
+	}
+	if a.CredentialID == "" {
+		return nil, fmt.Errorf("adapter instance %s has no credential", a.ID)
+	}
+	key := a.Key.payload()
+	m := map[string]interface{}{
+		"id":                  a.ID,
+		"name":                a.Key.Name,
+		"description":         a.Description,
+		"adapterKindKey":      a.Key.AdapterKindKey,
+		"resourceIdentifiers": key["resourceIdentifiers"],
+		"credential": map[string]interface{}{
+			"id": a.CredentialID,
+		},
+	}
+	if a.CollectorID != 0 {
+		m["collectorId"] = a.CollectorID
+	}
+	if a.CollectorGroupID != "" {
+		m["collectorGroupId"] = a.CollectorGroupID
+	}
+	return m, nil
+}
+
+// GetAdapterInstanceName returns the name of the adapter instance with
+// the provided identifier, e.g. ResourceStatusState.AdapterInstanceID.
+// The names are cached by the client.
+func (c *Client) GetAdapterInstanceName(id string) (string, error) {
+	c.mu.Lock()
+	name, exists := c.adapterInstanceNames[id]
+	c.mu.Unlock()
+	if exists {
+		return name, nil
+	}
+	instance, err := c.GetAdapterInstance(id)
+	if err != nil {
+		return "", err
+	}
+	return instance.Name(), nil
+}
+
+func (c *Client) cacheAdapterInstanceName(id, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.adapterInstanceNames == nil {
+		c.adapterInstanceNames = make(map[string]string)
+	}
+	c.adapterInstanceNames[id] = name
+}
+
+// ToJSONString serializes AdapterInstance to a string.
+func (a *AdapterInstance) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(a)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	. "github.com/greenpau/go-vrop/internal/server"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGetAdapterInstances(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/adapters": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "adapters.json"},
+		},
+		"/suite-api/api/resources": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "resources_adapter_instances.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	instances, err := cli.GetAdapterInstances("VMWARE")
+	if err != nil {
+		t.Fatalf("failed getting adapter instances: %s", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("expected 2 adapter instances, got %d", len(instances))
+	}
+	if instances[0].State != StateStarted || instances[0].Status != StatusDataReceiving {
+		t.Fatalf("unexpected state of %s: %s %s", instances[0].Name(), instances[0].State, instances[0].Status)
+	}
+	// The state reported by another adapter instance is not the state
+	// of the adapter instance.
+	if instances[1].State != "" || instances[1].Status != "" {
+		t.Fatalf("unexpected state of %s: %s %s", instances[1].Name(), instances[1].State, instances[1].Status)
+	}
+
+	name, err := cli.GetAdapterInstanceName("a1b2c3d4-0002")
+	if err != nil {
+		t.Fatalf("failed getting adapter instance name: %s", err)
+	}
+	if name != "vcenter02" {
+		t.Fatalf("expected cached name vcenter02, got %s", name)
+	}
+}
+
+func TestAdapterInstanceNamesConcurrency(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/adapters/a1b2c3d4-0001": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "adapter_instance.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	// The goroutines authenticate concurrently.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if name, err := cli.GetAdapterInstanceName("a1b2c3d4-0001"); err != nil || name != "vcenter01" {
+				t.Errorf("unexpected adapter instance name %q: %v", name, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTestAdapterInstanceConnection(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/adapters/a1b2c3d4-0001": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "adapter_instance.json"},
+		},
+		"/suite-api/api/adapters/testConnection": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "POST"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	if err := cli.TestAdapterInstanceConnection("a1b2c3d4-0001"); err != nil {
+		t.Fatalf("failed testing adapter instance connection: %s", err)
+	}
+
+	var body []byte
+	for _, req := range server.Requests() {
+		if strings.HasPrefix(req.RequestURI, "/suite-api/api/adapters/testConnection") {
+			body = req.Body
+		}
+	}
+	payload := map[string]interface{}{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed unpacking request payload: %s", err)
+	}
+	credential, ok := payload["credential"].(map[string]interface{})
+	if !ok || credential["id"] != "c9d8e7f6-0001" {
+		t.Fatalf("expected credential reference in payload: %s", body)
+	}
+	if payload["name"] != "vcenter01" || payload["adapterKindKey"] != "VMWARE" {
+		t.Fatalf("unexpected payload: %s", body)
+	}
+	ids, ok := payload["resourceIdentifiers"].([]interface{})
+	if !ok || len(ids) != 2 {
+		t.Fatalf("expected 2 resource identifiers in payload: %s", body)
+	}
+}
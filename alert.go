@@ -0,0 +1,275 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// The criticality levels of alerts.
+const (
+	AlertCriticalityCritical    = "CRITICAL"
+	AlertCriticalityImmediate   = "IMMEDIATE"
+	AlertCriticalityWarning     = "WARNING"
+	AlertCriticalityInformation = "INFORMATION"
+)
+
+// The statuses of alerts.
+const (
+	AlertStatusActive   = "ACTIVE"
+	AlertStatusCanceled = "CANCELED"
+)
+
+// The impacts of alerts, i.e. the badges affected by alerts.
+const (
+	AlertImpactHealth     = "HEALTH"
+	AlertImpactRisk       = "RISK"
+	AlertImpactEfficiency = "EFFICIENCY"
+)
+
+// Alert is an alert raised for a resource.
+type Alert struct {
+	// Identifier of the Alert.
+	ID string `json:"alertId,omitempty"`
+	// Identifier of the Resource the Alert was raised for.
+	ResourceID string `json:"resourceId,omitempty"`
+	// The criticality of the Alert, e.g. CRITICAL, IMMEDIATE, WARNING,
+	// INFORMATION.
+	Level string `json:"alertLevel,omitempty"`
+	// The type and subtype of the Alert.
+	Type    int `json:"type,omitempty"`
+	SubType int `json:"subType,omitempty"`
+	// The status of the Alert, i.e. ACTIVE or CANCELED.
+	Status string `json:"status,omitempty"`
+	// The control state of the Alert, e.g. OPEN, ASSIGNED, SUSPENDED,
+	// SUPPRESSED.
+	ControlState string `json:"controlState,omitempty"`
+	// The badge affected by the Alert, i.e. HEALTH, RISK, EFFICIENCY.
+	Impact string `json:"alertImpact,omitempty"`
+	// The definition of the Alert.
+	DefinitionID   string `json:"alertDefinitionId,omitempty"`
+	DefinitionName string `json:"alertDefinitionName,omitempty"`
+	// The user the Alert is assigned to.
+	OwnerID   string `json:"ownerId,omitempty"`
+	OwnerName string `json:"ownerName,omitempty"`
+	// The time the Alert was raised, updated, canceled, and
+	// suspended until.
+	StartTime        Timestamp `json:"startTimeUTC"`
+	UpdateTime       Timestamp `json:"updateTimeUTC"`
+	CancelTime       Timestamp `json:"cancelTimeUTC"`
+	SuspendUntilTime Timestamp `json:"suspendUntilTimeUTC"`
+	// Set of useful links related to the current object.
+	Links []*Link `json:"links,omitempty"`
+}
+
+// AlertsResponse is a response with alerts.
+type AlertsResponse struct {
+	Page   *PageInfo `json:"pageInfo,omitempty"`
+	Links  []*Link   `json:"links,omitempty"`
+	Alerts []*Alert  `json:"alerts,omitempty"`
+}
+
+// AlertFilter is the criteria for selecting alerts. Empty fields
+// do not restrict the selection.
+type AlertFilter struct {
+	// The identifiers of alerts.
+	AlertIDs []string
+	// The identifiers of the resources the alerts were raised for.
+	ResourceIDs []string
+	// The status of alerts, i.e. ACTIVE or CANCELED.
+	Status string
+	// The criticality levels of alerts.
+	Criticality []string
+	// The badge affected by alerts, i.e. HEALTH, RISK, EFFICIENCY.
+	Impact string
+	// Select only active alerts.
+	ActiveOnly bool
+	// The time range the alerts were raised in.
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// GetAlerts returns a list of alerts matching the filter.
+func (c *Client) GetAlerts(filter *AlertFilter) ([]*Alert, error) {
+	alerts := []*Alert{}
+	if filter == nil {
+		filter = &AlertFilter{}
+	}
+	query, err := filter.query()
+	if err != nil {
+		return alerts, err
+	}
+	if err := c.authenticate(); err != nil {
+		return alerts, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := make(map[string]string)
+		params["page"] = strconv.Itoa(pageOffset)
+		params["pageSize"] = strconv.Itoa(pageSize)
+		b, err := c.requestWithPayload("POST", "alerts/query", params, query)
+		if err != nil {
+			return alerts, err
+		}
+
+		resp := &AlertsResponse{}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return alerts, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+
+		alerts = append(alerts, resp.Alerts...)
+
+		if len(resp.Alerts) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return alerts, nil
+}
+
+// GetAlert returns the alert with the provided identifier.
+func (c *Client) GetAlert(id string) (*Alert, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty alert id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.request("GET", "alerts/"+id, params)
+	if err != nil {
+		return nil, err
+	}
+	alert := &Alert{}
+	if err := json.Unmarshal(b, alert); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return alert, nil
+}
+
+// AcknowledgeAlerts acknowledges the alerts with the provided identifiers.
+func (c *Client) AcknowledgeAlerts(ids []string) error {
+	return c.modifyAlerts("acknowledge", ids, nil)
+}
+
+// CancelAlerts cancels the alerts with the provided identifiers.
+func (c *Client) CancelAlerts(ids []string) error {
+	return c.modifyAlerts("cancel", ids, nil)
+}
+
+// SuspendAlerts suspends the alerts with the provided identifiers
+// for the provided duration.
+func (c *Client) SuspendAlerts(ids []string, duration time.Duration) error {
+	minutes := int(duration / time.Minute)
+	if minutes < 1 {
+		return fmt.Errorf("invalid alert suspension duration: %s", duration)
+	}
+	params := map[string]string{
+		"minutes": strconv.Itoa(minutes),
+	}
+	return c.modifyAlerts("suspend", ids, params)
+}
+
+func (c *Client) modifyAlerts(action string, ids []string, opts map[string]string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("no alert ids to %s", action)
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	for k, v := range opts {
+		params[k] = v
+	}
+	params["action"] = action
+	payload := map[string][]string{
+		"uuids": ids,
+	}
+	if _, err := c.requestWithPayload("POST", "alerts", params, payload); err != nil {
+		return fmt.Errorf("failed to %s alerts: %s", action, err)
+	}
+	return nil
+}
+
+// query returns the payload of alerts query API call.
+func (f *AlertFilter) query() (map[string]interface{}, error) {
+	q := make(map[string]interface{})
+	if len(f.AlertIDs) > 0 {
+		q["alertId"] = f.AlertIDs
+	}
+	if len(f.ResourceIDs) > 0 {
+		q["resourceId"] = f.ResourceIDs
+	}
+	switch f.Status {
+	case "":
+	case AlertStatusActive, AlertStatusCanceled:
+		q["alertStatus"] = f.Status
+	default:
+		return nil, fmt.Errorf("unsupported alert status: %s", f.Status)
+	}
+	for _, level := range f.Criticality {
+		switch level {
+		case AlertCriticalityCritical, AlertCriticalityImmediate, AlertCriticalityWarning, AlertCriticalityInformation:
+		default:
+			return nil, fmt.Errorf("unsupported alert criticality: %s", level)
+		}
+	}
+	if len(f.Criticality) > 0 {
+		q["alertCriticality"] = f.Criticality
+	}
+	switch f.Impact {
+	case "":
+	case AlertImpactHealth, AlertImpactRisk, AlertImpactEfficiency:
+		q["alertImpact"] = map[string]string{
+			"impactType": "BADGE",
+			"detail":     f.Impact,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported alert impact: %s", f.Impact)
+	}
+	if f.ActiveOnly {
+		q["activeOnly"] = true
+	}
+	if !f.StartTime.IsZero() || !f.EndTime.IsZero() {
+		if !f.StartTime.IsZero() && !f.EndTime.IsZero() && f.EndTime.Before(f.StartTime) {
+			return nil, fmt.Errorf("alert time range end %s precedes start %s", f.EndTime, f.StartTime)
+		}
+		timeRange := make(map[string]int64)
+		if !f.StartTime.IsZero() {
+			timeRange["startTime"] = timeToEpochMillis(f.StartTime)
+		}
+		if !f.EndTime.IsZero() {
+			timeRange["endTime"] = timeToEpochMillis(f.EndTime)
+		}
+		q["startTimeRange"] = timeRange
+	}
+	return q, nil
+}
+
+// ToJSONString serializes Alert to a string.
+func (a *Alert) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(a)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
@@ -0,0 +1,402 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// AlertDefinition is the definition of an alert.
+type AlertDefinition struct {
+	// Identifier of the AlertDefinition, assigned by the server.
+	ID string `json:"id,omitempty"`
+	// Name and description of the AlertDefinition.
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Adapter Kind and Resource Kind the AlertDefinition applies to.
+	AdapterKindKey  string `json:"adapterKindKey"`
+	ResourceKindKey string `json:"resourceKindKey"`
+	// The number of collection cycles the symptoms must be present
+	// before the alert is raised, and absent before it is canceled.
+	WaitCycles   int `json:"waitCycles,omitempty"`
+	CancelCycles int `json:"cancelCycles,omitempty"`
+	// The type and subtype of the alert.
+	Type    int `json:"type,omitempty"`
+	SubType int `json:"subType,omitempty"`
+	// The states of the AlertDefinition.
+	States []*AlertDefinitionState `json:"states,omitempty"`
+	// Extra holds the values of the keys not supported by this package,
+	// so that they are sent back on update.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AlertDefinitionState is a state of an alert definition, i.e. the
+// severity of the alert raised when its symptom set is triggered.
+type AlertDefinitionState struct {
+	// The severity of the alert, e.g. CRITICAL, IMMEDIATE, WARNING,
+	// INFORMATION, AUTO.
+	Severity string `json:"severity,omitempty"`
+	// The symptom set triggering the state.
+	SymptomSet *SymptomSet `json:"base-symptom-set,omitempty"`
+	// The impact of the alert.
+	Impact *AlertImpact `json:"impact,omitempty"`
+	// The priorities of recommendations, keyed by recommendation identifier.
+	RecommendationPriorities map[string]int `json:"recommendationPriorityMap,omitempty"`
+	// Extra holds the values of the keys not supported by this package.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// SymptomSet is a set of symptoms triggering an alert.
+type SymptomSet struct {
+	// The type of the set, i.e. SYMPTOM_SET or SYMPTOM_SET_COMPOSITE.
+	Type string `json:"type,omitempty"`
+	// The relation of the resources with the symptoms to the resource of
+	// the alert, e.g. SELF, PARENT, CHILD, ANCESTOR, DESCENDANT.
+	Relation string `json:"relation,omitempty"`
+	// The aggregation over related resources, e.g. ALL, ANY.
+	Aggregation string `json:"aggregation,omitempty"`
+	// The value and type of the aggregation, e.g. COUNT or PERCENT.
+	Value     float64 `json:"value,omitempty"`
+	ValueType string  `json:"valueType,omitempty"`
+	// The operator applied to the symptoms of the set, i.e. AND or OR.
+	SymptomSetOperator string `json:"symptomSetOperator,omitempty"`
+	// The symptoms of the set.
+	SymptomDefinitionIDs []string `json:"symptomDefinitionIds,omitempty"`
+	// The operator applied to the nested sets of a composite set.
+	Operator string `json:"operator,omitempty"`
+	// The nested sets of a composite set.
+	SymptomSets []*SymptomSet `json:"symptom-sets,omitempty"`
+	// Extra holds the values of the keys not supported by this package.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AlertImpact is the impact of an alert.
+type AlertImpact struct {
+	// The type of the impact, i.e. BADGE.
+	Type string `json:"impactType,omitempty"`
+	// The badge affected by the alert, i.e. HEALTH, RISK, EFFICIENCY.
+	Detail string `json:"detail,omitempty"`
+	// Extra holds the values of the keys not supported by this package.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AlertDefinitionsResponse is a response with alert definitions.
+type AlertDefinitionsResponse struct {
+	Page             *PageInfo          `json:"pageInfo,omitempty"`
+	Links            []*Link            `json:"links,omitempty"`
+	AlertDefinitions []*AlertDefinition `json:"alertDefinitions,omitempty"`
+}
+
+// GetAlertDefinitions returns a list of alert definitions. The options
+// filter the definitions by adapter kind (adapter_kind) and resource
+// kind (resource_kind).
+func (c *Client) GetAlertDefinitions(opts map[string]interface{}) ([]*AlertDefinition, error) {
+	definitions := []*AlertDefinition{}
+	filter, err := definitionFilter(opts)
+	if err != nil {
+		return definitions, err
+	}
+	if err := c.authenticate(); err != nil {
+		return definitions, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := make(map[string]string)
+		for k, v := range filter {
+			params[k] = v
+		}
+		params["page"] = strconv.Itoa(pageOffset)
+		params["pageSize"] = strconv.Itoa(pageSize)
+		b, err := c.request("GET", "alertdefinitions", params)
+		if err != nil {
+			return definitions, err
+		}
+
+		resp := &AlertDefinitionsResponse{}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return definitions, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+
+		definitions = append(definitions, resp.AlertDefinitions...)
+
+		if len(resp.AlertDefinitions) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return definitions, nil
+}
+
+// GetAlertDefinition returns the alert definition with the provided identifier.
+func (c *Client) GetAlertDefinition(id string) (*AlertDefinition, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty alert definition id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.request("GET", "alertdefinitions/"+id, params)
+	if err != nil {
+		return nil, err
+	}
+	d := &AlertDefinition{}
+	if err := json.Unmarshal(b, d); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return d, nil
+}
+
+// CreateAlertDefinition creates the alert definition and returns it
+// with the identifier assigned by the server.
+func (c *Client) CreateAlertDefinition(d *AlertDefinition) (*AlertDefinition, error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	payload := *d
+	payload.ID = ""
+	params := make(map[string]string)
+	b, err := c.requestWithPayload("POST", "alertdefinitions", params, &payload)
+	if err != nil {
+		return nil, err
+	}
+	created := &AlertDefinition{}
+	if err := json.Unmarshal(b, created); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return created, nil
+}
+
+// UpdateAlertDefinition updates the alert definition.
+func (c *Client) UpdateAlertDefinition(d *AlertDefinition) (*AlertDefinition, error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+	if d.ID == "" {
+		return nil, fmt.Errorf("empty alert definition id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.requestWithPayload("PUT", "alertdefinitions", params, d)
+	if err != nil {
+		return nil, err
+	}
+	updated := &AlertDefinition{}
+	if err := json.Unmarshal(b, updated); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return updated, nil
+}
+
+// DeleteAlertDefinition deletes the alert definition with the provided identifier.
+func (c *Client) DeleteAlertDefinition(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty alert definition id")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	if _, err := c.request("DELETE", "alertdefinitions/"+id, params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ApplyAlertDefinition creates or updates the alert definition so that the
+// server has the same definition. The existing definition is looked up by
+// identifier, then by name. The definition is not updated when it already
+// matches. The fields of the existing definition not supported by this
+// package are kept on update. It returns the applied definition and whether
+// it was changed.
+func (c *Client) ApplyAlertDefinition(d *AlertDefinition, existing []*AlertDefinition) (*AlertDefinition, bool, error) {
+	items := []definition{}
+	for _, item := range existing {
+		items = append(items, item)
+	}
+	applied, changed, err := applyDefinition(d, items,
+		func(x definition) (definition, error) {
+			return c.CreateAlertDefinition(x.(*AlertDefinition))
+		},
+		func(x definition) (definition, error) {
+			return c.UpdateAlertDefinition(x.(*AlertDefinition))
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	return applied.(*AlertDefinition), changed, nil
+}
+
+func (d *AlertDefinition) identity() definitionIdentity {
+	return definitionIdentity{
+		ID:              d.ID,
+		Name:            d.Name,
+		AdapterKindKey:  d.AdapterKindKey,
+		ResourceKindKey: d.ResourceKindKey,
+	}
+}
+
+func (d *AlertDefinition) desired(current definition) (definition, error) {
+	cur := current.(*AlertDefinition)
+	desired := &AlertDefinition{}
+	if err := jsonCopy(d, desired); err != nil {
+		return nil, err
+	}
+	desired.ID = cur.ID
+	desired.Extra = inheritExtra(desired.Extra, cur.Extra)
+	for i, state := range desired.States {
+		if i < len(cur.States) {
+			state.inheritExtra(cur.States[i])
+		}
+	}
+	return desired, nil
+}
+
+func (s *AlertDefinitionState) inheritExtra(current *AlertDefinitionState) {
+	if s == nil || current == nil {
+		return
+	}
+	s.Extra = inheritExtra(s.Extra, current.Extra)
+	s.SymptomSet.inheritExtra(current.SymptomSet)
+	if s.Impact != nil && current.Impact != nil {
+		s.Impact.Extra = inheritExtra(s.Impact.Extra, current.Impact.Extra)
+	}
+}
+
+func (s *SymptomSet) inheritExtra(current *SymptomSet) {
+	if s == nil || current == nil {
+		return
+	}
+	s.Extra = inheritExtra(s.Extra, current.Extra)
+	for i, set := range s.SymptomSets {
+		if i < len(current.SymptomSets) {
+			set.inheritExtra(current.SymptomSets[i])
+		}
+	}
+}
+
+// Equal returns true when the alert definitions have the same JSON representation.
+func (d *AlertDefinition) Equal(other *AlertDefinition) bool {
+	return jsonEqual(d, other)
+}
+
+// UnmarshalJSON unpacks AlertDefinition. The keys not supported by this
+// package are preserved in Extra.
+func (d *AlertDefinition) UnmarshalJSON(b []byte) error {
+	type alias AlertDefinition
+	extra, err := unpackWithExtra(b, (*alias)(d))
+	if err != nil {
+		return fmt.Errorf("failed to unpack AlertDefinition: %s", err)
+	}
+	d.Extra = extra
+	return nil
+}
+
+// MarshalJSON packs AlertDefinition with the keys preserved in Extra.
+func (d AlertDefinition) MarshalJSON() ([]byte, error) {
+	type alias AlertDefinition
+	return packWithExtra(alias(d), d.Extra)
+}
+
+// UnmarshalJSON unpacks AlertDefinitionState. The keys not supported by
+// this package are preserved in Extra.
+func (s *AlertDefinitionState) UnmarshalJSON(b []byte) error {
+	type alias AlertDefinitionState
+	extra, err := unpackWithExtra(b, (*alias)(s))
+	if err != nil {
+		return fmt.Errorf("failed to unpack AlertDefinitionState: %s", err)
+	}
+	s.Extra = extra
+	return nil
+}
+
+// MarshalJSON packs AlertDefinitionState with the keys preserved in Extra.
+func (s AlertDefinitionState) MarshalJSON() ([]byte, error) {
+	type alias AlertDefinitionState
+	return packWithExtra(alias(s), s.Extra)
+}
+
+// UnmarshalJSON unpacks SymptomSet. The keys not supported by this package
+// are preserved in Extra.
+func (s *SymptomSet) UnmarshalJSON(b []byte) error {
+	type alias SymptomSet
+	extra, err := unpackWithExtra(b, (*alias)(s))
+	if err != nil {
+		return fmt.Errorf("failed to unpack SymptomSet: %s", err)
+	}
+	s.Extra = extra
+	return nil
+}
+
+// MarshalJSON packs SymptomSet with the keys preserved in Extra.
+func (s SymptomSet) MarshalJSON() ([]byte, error) {
+	type alias SymptomSet
+	return packWithExtra(alias(s), s.Extra)
+}
+
+// UnmarshalJSON unpacks AlertImpact. The keys not supported by this package
+// are preserved in Extra.
+func (i *AlertImpact) UnmarshalJSON(b []byte) error {
+	type alias AlertImpact
+	extra, err := unpackWithExtra(b, (*alias)(i))
+	if err != nil {
+		return fmt.Errorf("failed to unpack AlertImpact: %s", err)
+	}
+	i.Extra = extra
+	return nil
+}
+
+// MarshalJSON packs AlertImpact with the keys preserved in Extra.
+func (i AlertImpact) MarshalJSON() ([]byte, error) {
+	type alias AlertImpact
+	return packWithExtra(alias(i), i.Extra)
+}
+
+// ToJSONString serializes AlertDefinition to a string.
+func (d *AlertDefinition) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(d)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
+
+func (d *AlertDefinition) validate() error {
+	if d == nil {
+		return fmt.Errorf("nil alert definition")
+	}
+	if d.Name == "" {
+		return fmt.Errorf("alert definition name is empty")
+	}
+	if d.AdapterKindKey == "" || d.ResourceKindKey == "" {
+		return fmt.Errorf("alert definition %s has no adapter kind or resource kind", d.Name)
+	}
+	if len(d.States) == 0 {
+		return fmt.Errorf("alert definition %s has no states", d.Name)
+	}
+	return nil
+}
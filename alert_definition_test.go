@@ -0,0 +1,233 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	. "github.com/greenpau/go-vrop/internal/server"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDefinitionsRoundTrip verifies that the symptom and alert definitions
+// in the API responses are packed back unchanged, e.g. when exported to
+// a file and applied back.
+func TestDefinitionsRoundTrip(t *testing.T) {
+	testcases := []struct {
+		fileName string
+		key      string
+		resp     interface{}
+		items    func(interface{}) interface{}
+	}{
+		{
+			fileName: "alert_definitions.json",
+			key:      "alertDefinitions",
+			resp:     &AlertDefinitionsResponse{},
+			items: func(resp interface{}) interface{} {
+				return resp.(*AlertDefinitionsResponse).AlertDefinitions
+			},
+		},
+		{
+			fileName: "symptom_definitions.json",
+			key:      "symptomDefinitions",
+			resp:     &SymptomDefinitionsResponse{},
+			items: func(resp interface{}) interface{} {
+				return resp.(*SymptomDefinitionsResponse).SymptomDefinitions
+			},
+		},
+	}
+	for _, tc := range testcases {
+		b, err := ioutil.ReadFile("testdata/responses/" + tc.fileName)
+		if err != nil {
+			t.Fatalf("failed reading test data: %s", err)
+		}
+		var golden map[string]interface{}
+		if err := json.Unmarshal(b, &golden); err != nil {
+			t.Fatalf("%s: failed unmarshalling test data: %s", tc.fileName, err)
+		}
+		if err := json.Unmarshal(b, tc.resp); err != nil {
+			t.Fatalf("%s: failed unpacking test data: %s", tc.fileName, err)
+		}
+		packed, err := json.Marshal(tc.items(tc.resp))
+		if err != nil {
+			t.Fatalf("%s: failed packing: %s", tc.fileName, err)
+		}
+		var got interface{}
+		if err := json.Unmarshal(packed, &got); err != nil {
+			t.Fatalf("%s: failed unmarshalling: %s", tc.fileName, err)
+		}
+		if !reflect.DeepEqual(got, golden[tc.key]) {
+			want, _ := json.Marshal(golden[tc.key])
+			t.Fatalf("%s: packed definitions differ:\ngot:  %s\nwant: %s", tc.fileName, packed, want)
+		}
+	}
+}
+
+func TestApplyAlertDefinition(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/alertdefinitions": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "POST", FileName: "alert_definition.json"},
+			&MockTestEndpoint{Method: "PUT", FileName: "alert_definition.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	b, err := ioutil.ReadFile("testdata/responses/alert_definition.json")
+	if err != nil {
+		t.Fatalf("failed reading test data: %s", err)
+	}
+	d := &AlertDefinition{}
+	if err := json.Unmarshal(b, d); err != nil {
+		t.Fatalf("failed unpacking test data: %s", err)
+	}
+	// The definition kept in a file has no identifier assigned by the server.
+	d.ID = ""
+
+	created, changed, err := cli.ApplyAlertDefinition(d, nil)
+	if err != nil {
+		t.Fatalf("failed applying alert definition: %s", err)
+	}
+	if !changed || created.ID == "" {
+		t.Fatalf("expected the alert definition to be created, got changed %t, id %q", changed, created.ID)
+	}
+
+	// Applying the same definition again does not change it.
+	existing := []*AlertDefinition{created}
+	applied, changed, err := cli.ApplyAlertDefinition(d, existing)
+	if err != nil {
+		t.Fatalf("failed applying alert definition: %s", err)
+	}
+	if changed || applied.ID != created.ID {
+		t.Fatalf("expected the alert definition to be unchanged, got changed %t, id %q", changed, applied.ID)
+	}
+
+	modified := *d
+	modified.WaitCycles = 3
+	if _, changed, err = cli.ApplyAlertDefinition(&modified, existing); err != nil {
+		t.Fatalf("failed applying alert definition: %s", err)
+	}
+	if !changed {
+		t.Fatalf("expected the alert definition to be updated")
+	}
+
+	var methods []string
+	for _, req := range server.Requests() {
+		if req.RequestURI == "/suite-api/api/alertdefinitions?" {
+			methods = append(methods, req.Method)
+		}
+	}
+	if !reflect.DeepEqual(methods, []string{"POST", "PUT"}) {
+		t.Fatalf("expected POST and PUT requests, got: %v", methods)
+	}
+}
+
+// TestApplyDefinitionsKeepExtraFields verifies that the fields of the
+// definitions on the server not supported by this package are neither
+// reported as a change nor dropped on update.
+func TestApplyDefinitionsKeepExtraFields(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/alertdefinitions": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "PUT", FileName: "alert_definition.json"},
+		},
+		"/suite-api/api/symptomdefinitions": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "PUT", FileName: "symptom_definition.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	// withExtra returns the fixture as on the server, i.e. with the fields
+	// not supported by this package, and as kept in a file, i.e. without.
+	withExtra := func(fileName string, add func(m map[string]interface{}), onServer, inFile interface{}) {
+		b, err := ioutil.ReadFile("testdata/responses/" + fileName)
+		if err != nil {
+			t.Fatalf("failed reading test data: %s", err)
+		}
+		if err := json.Unmarshal(b, inFile); err != nil {
+			t.Fatalf("failed unpacking test data: %s", err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatalf("failed unmarshalling test data: %s", err)
+		}
+		add(m)
+		if b, err = json.Marshal(m); err != nil {
+			t.Fatalf("failed marshalling test data: %s", err)
+		}
+		if err := json.Unmarshal(b, onServer); err != nil {
+			t.Fatalf("failed unpacking test data: %s", err)
+		}
+	}
+
+	currentAlert, alert := &AlertDefinition{}, &AlertDefinition{}
+	withExtra("alert_definition.json", func(m map[string]interface{}) {
+		m["ignoreWhenAcknowledged"] = true
+		state := m["states"].([]interface{})[0].(map[string]interface{})
+		state["suppressNotifications"] = false
+		state["base-symptom-set"].(map[string]interface{})["symptomSetVersion"] = 2.0
+		state["impact"].(map[string]interface{})["impactScore"] = 10.0
+	}, currentAlert, alert)
+	currentSymptom, symptom := &SymptomDefinition{}, &SymptomDefinition{}
+	withExtra("symptom_definition.json", func(m map[string]interface{}) {
+		condition := m["state"].(map[string]interface{})["condition"].(map[string]interface{})
+		condition["targetKey"] = "guestfilesystem|capacity"
+	}, currentSymptom, symptom)
+	alert.ID, symptom.ID = "", ""
+
+	if _, changed, err := cli.ApplyAlertDefinition(alert, []*AlertDefinition{currentAlert}); err != nil || changed {
+		t.Fatalf("expected the alert definition to be unchanged, got changed %t: %v", changed, err)
+	}
+	if _, changed, err := cli.ApplySymptomDefinition(symptom, []*SymptomDefinition{currentSymptom}); err != nil || changed {
+		t.Fatalf("expected the symptom definition to be unchanged, got changed %t: %v", changed, err)
+	}
+
+	alert.WaitCycles = 3
+	if _, changed, err := cli.ApplyAlertDefinition(alert, []*AlertDefinition{currentAlert}); err != nil || !changed {
+		t.Fatalf("expected the alert definition to be updated, got changed %t: %v", changed, err)
+	}
+	symptom.State.Condition.Value = "95.0"
+	if _, changed, err := cli.ApplySymptomDefinition(symptom, []*SymptomDefinition{currentSymptom}); err != nil || !changed {
+		t.Fatalf("expected the symptom definition to be updated, got changed %t: %v", changed, err)
+	}
+
+	bodies := map[string][]byte{}
+	for _, req := range server.Requests() {
+		if req.Method == "PUT" {
+			bodies[req.RequestURI] = req.Body
+		}
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 updates, got %d", len(bodies))
+	}
+	for _, s := range []string{
+		`"waitCycles":3`,
+		`"ignoreWhenAcknowledged":true`,
+		`"suppressNotifications":false`,
+		`"symptomSetVersion":2`,
+		`"impactScore":10`,
+	} {
+		if body := bodies["/suite-api/api/alertdefinitions?"]; !strings.Contains(string(body), s) {
+			t.Fatalf("expected %s in alert definition update: %s", s, body)
+		}
+	}
+	for _, s := range []string{`"value":"95.0"`, `"targetKey":"guestfilesystem|capacity"`} {
+		if body := bodies["/suite-api/api/symptomdefinitions?"]; !strings.Contains(string(body), s) {
+			t.Fatalf("expected %s in symptom definition update: %s", s, body)
+		}
+	}
+}
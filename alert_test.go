@@ -0,0 +1,52 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestAlertsResponse(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/responses/alerts.json")
+	if err != nil {
+		t.Fatalf("failed reading test data: %s", err)
+	}
+	resp := &AlertsResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		t.Fatalf("failed unpacking alerts: %s", err)
+	}
+	if len(resp.Alerts) != 2 {
+		t.Fatalf("expected 2 alerts, found %d", len(resp.Alerts))
+	}
+	alert := resp.Alerts[0]
+	if alert.Type != 15 || alert.SubType != 19 {
+		t.Fatalf("unexpected alert type and subtype: %d, %d", alert.Type, alert.SubType)
+	}
+	if alert.Level != AlertCriticalityCritical || alert.Status != AlertStatusActive {
+		t.Fatalf("unexpected alert level and status: %s, %s", alert.Level, alert.Status)
+	}
+	if !alert.StartTime.Equal(time.Unix(1607712145, 337*int64(time.Millisecond))) {
+		t.Fatalf("unexpected alert start time: %s", alert.StartTime)
+	}
+	if !alert.CancelTime.IsZero() {
+		t.Fatalf("unexpected alert cancel time: %s", alert.CancelTime)
+	}
+	if resp.Alerts[1].OwnerName != "admin" || resp.Alerts[1].Impact != AlertImpactRisk {
+		t.Fatalf("unexpected alert: %v", resp.Alerts[1])
+	}
+}
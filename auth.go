@@ -35,8 +35,16 @@ type AuthResponse struct {
 	Roles     []interface{} `json:"roles,omitempty"`
 }
 
+// authToken returns the authentication token, or an empty string when the
+// client is not authenticated.
+func (c *Client) authToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
 func (c *Client) authenticate() error {
-	if c.token != "" {
+	if c.authToken() != "" {
 		return nil
 	}
 	reqURL := fmt.Sprintf("%s%sauth/token/acquire", c.url, c.pathPrefix)
@@ -110,15 +118,18 @@ func (c *Client) authenticate() error {
 		return fmt.Errorf("token not found in authentication response")
 	}
 
-	c.token = authResp.Token
-
 	secs := int64(authResp.Validity / 1000)
 	nsecs := int64(((authResp.Validity / 1000) - float64(secs)) * 1e9)
-	c.tokenExpiresAt = time.Unix(secs, nsecs)
+	expiresAt := time.Unix(secs, nsecs)
+
+	c.mu.Lock()
+	c.token = authResp.Token
+	c.tokenExpiresAt = expiresAt
+	c.mu.Unlock()
 
 	c.log.Debug(
 		"authenticated successfully",
-		zap.String("token_expires_at", c.tokenExpiresAt.String()),
+		zap.String("token_expires_at", expiresAt.String()),
 	)
 
 	return nil
@@ -1,44 +1,66 @@
 package vrop
 
 import (
-	"fmt"
+	"encoding/json"
 )
 
 // Badge is a major or minor badge.
 type Badge struct {
 	// Type of the Badge
-	Type string `json:"type,omitempty"`
+	Type BadgeType `json:"type,omitempty"`
 	// Color of the Badge as determined by the system
-	Color string `json:"color,omitempty"`
+	Color Color `json:"color,omitempty"`
 	// Score (value) associated with the Badge. This number represents the
 	// absolute value of the Badge. Typically the value is between 0-100
 	// but this is not the case all the time.
 	Score float64 `json:"score,omitempty"`
+	// Extra holds the values of the keys not supported by this package,
+	// when decoded in lenient mode.
+	Extra map[string]json.RawMessage `json:"-"`
+	// keys are the keys found in the response the Badge was unpacked from.
+	keys keySet
 }
 
-func unpackBadge(m interface{}) (*Badge, error) {
-	var pim map[string]interface{}
-
-	switch m.(type) {
-	case map[string]interface{}:
-		pim = m.(map[string]interface{})
-	default:
-		return nil, fmt.Errorf("map not found")
+func unpackBadge(m interface{}, path string, d *decoder) (*Badge, error) {
+	pim, err := asObject(m, path)
+	if err != nil {
+		return nil, err
 	}
 
-	p := &Badge{}
+	p := &Badge{keys: newKeySet(pim)}
 	for k, v := range pim {
 		switch k {
 		case "type":
-			p.Type = v.(string)
+			s, err := d.enum(v, joinPath(path, k), func(s string) bool { return BadgeType(s).Valid() })
+			if err != nil {
+				return nil, err
+			}
+			p.Type = BadgeType(s)
 		case "color":
-			p.Color = v.(string)
+			s, err := d.enum(v, joinPath(path, k), func(s string) bool { return Color(s).Valid() })
+			if err != nil {
+				return nil, err
+			}
+			p.Color = Color(s)
 		case "score":
-			p.Score = v.(float64)
+			if p.Score, err = asNumber(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		default:
-			return nil, fmt.Errorf("map contains unsupported key: %s", k)
+			if err := d.unknownKey(path, k, v, &p.Extra); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return p, nil
 }
+
+// wire returns Badge in the format of the API.
+func (p *Badge) wire() map[string]interface{} {
+	w := newWireObject(p.keys)
+	w.set("type", p.Type, p.Type != "")
+	w.set("color", p.Color, p.Color != "")
+	w.set("score", p.Score, p.Score != 0)
+	return withExtra(w.m, p.Extra)
+}
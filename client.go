@@ -17,6 +17,7 @@ package vrop
 import (
 	"fmt"
 	"go.uber.org/zap"
+	"sync"
 	"time"
 )
 
@@ -32,22 +33,35 @@ type Client struct {
 	protocol           string
 	username           string
 	password           string
-	token              string
-	tokenExpiresAt     time.Time
 	validateServerCert bool
+	lenientDecoding    bool
 	dataLimit          int64
+	reportPollInterval time.Duration
 	pathPrefix         string
+	casaPathPrefix     string
 	log                *zap.Logger
+	// mu guards the fields below, because the client may be shared by
+	// goroutines.
+	mu sync.Mutex
+	// token is the authentication token and tokenExpiresAt is its expiry.
+	token          string
+	tokenExpiresAt time.Time
+	// version caches the version of the server.
+	version *Version
+	// adapterInstanceNames caches the names of adapter instances.
+	adapterInstanceNames map[string]string
 }
 
 // NewClient returns an instance of Client.
 func NewClient(opts map[string]interface{}) (*Client, error) {
 	c := &Client{
-		host:       "vrop",
-		port:       443,
-		protocol:   "https",
-		pathPrefix: "/suite-api/api/",
-		dataLimit:  ReceiverDataLimit,
+		host:               "vrop",
+		port:               443,
+		protocol:           "https",
+		pathPrefix:         "/suite-api/api/",
+		casaPathPrefix:     "/casa/",
+		dataLimit:          ReceiverDataLimit,
+		reportPollInterval: 10 * time.Second,
 	}
 	log, err := newLogger(opts)
 	if err != nil {
@@ -71,6 +85,7 @@ func (c *Client) Info() {
 		"client configuration",
 		zap.String("url", c.url),
 		zap.String("path_prefix", c.pathPrefix),
+		zap.String("casa_path_prefix", c.casaPathPrefix),
 	)
 }
 
@@ -142,3 +157,22 @@ func (c *Client) SetValidateServerCertificate() error {
 	c.validateServerCert = true
 	return nil
 }
+
+// SetLenientDecoding instructs the client to tolerate the keys in API
+// responses not supported by this package, e.g. the fields added by newer
+// versions of the server. The values of such keys are preserved in the
+// Extra fields of the decoded objects, and logged as warnings.
+func (c *Client) SetLenientDecoding() error {
+	c.lenientDecoding = true
+	return nil
+}
+
+// SetReportPollInterval sets the interval between the checks of the status
+// of a report being generated, see WaitForReport.
+func (c *Client) SetReportPollInterval(d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("invalid report poll interval: %s", d)
+	}
+	c.reportPollInterval = d
+	return nil
+}
@@ -21,6 +21,31 @@ import (
 	"time"
 )
 
+// newMockClient returns a client of the mock server serving the provided
+// endpoints, in addition to the authentication endpoint.
+func newMockClient(t *testing.T, endpoints map[string][]*MockTestEndpoint) (*Client, *MockTestServer) {
+	cli, err := NewClient(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("failed initializing client: %s", err)
+	}
+	endpoints["/suite-api/api/auth/token/acquire"] = []*MockTestEndpoint{
+		&MockTestEndpoint{
+			Method:   "POST",
+			FileName: "auth_success.json",
+		},
+	}
+	server, err := NewMockTestServer(cli.log, endpoints, "", false)
+	if err != nil {
+		t.Fatalf("failed initializing mock test server: %s", err)
+	}
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("password123")
+	return cli, server
+}
+
 func TestClient(t *testing.T) {
 	timerStartTime := time.Now()
 	username := "admin"
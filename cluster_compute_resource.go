@@ -0,0 +1,79 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ClusterComputeResource is a vSphere compute cluster.
+type ClusterComputeResource struct {
+	InventoryItem
+	// Whether DRS is enabled, and its default automation level, e.g.
+	// fullyAutomated.
+	DRSEnabled         bool   `json:"drs_enabled,omitempty"`
+	DRSAutomationLevel string `json:"drs_automation_level,omitempty"`
+	// Whether vSphere HA is enabled.
+	HAEnabled bool `json:"ha_enabled,omitempty"`
+	// The names of the parents of the cluster.
+	ParentDatacenter string `json:"parent_datacenter,omitempty"`
+	ParentVCenter    string `json:"parent_vcenter,omitempty"`
+}
+
+// GetClusterComputeResources returns a list of ClusterComputeResource
+// instances, selected by the options of GetResources, e.g. name or tags.
+func (c *Client) GetClusterComputeResources(opts map[string]interface{}) ([]*ClusterComputeResource, error) {
+	clusters := []*ClusterComputeResource{}
+	err := c.getInventory("VMWARE", "ClusterComputeResource", opts, func() inventoryResource {
+		m := &ClusterComputeResource{}
+		clusters = append(clusters, m)
+		return m
+	})
+	return clusters, err
+}
+
+// parse populates the typed fields of ClusterComputeResource from its
+// properties.
+func (m *ClusterComputeResource) parse(r *Resource) []error {
+	p := &propertyParser{kind: "ClusterComputeResource"}
+	for k, v := range m.Properties {
+		if v == "" {
+			continue
+		}
+		switch k {
+		case "configuration|drsconfig|enabled":
+			m.DRSEnabled = p.parseBool(k, v)
+		case "configuration|drsconfig|defaultVmBehavior":
+			m.DRSAutomationLevel = v
+		case "configuration|dasconfig|enabled":
+			m.HAEnabled = p.parseBool(k, v)
+		case "summary|parentDatacenter":
+			m.ParentDatacenter = v
+		case "summary|parentVcenter":
+			m.ParentVCenter = v
+		}
+	}
+	return p.errors
+}
+
+// ToJSONString serializes ClusterComputeResource to a string.
+func (m *ClusterComputeResource) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
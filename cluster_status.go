@@ -0,0 +1,175 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The online states of the cluster and its nodes.
+const (
+	ClusterOnline  = "ONLINE"
+	ClusterOffline = "OFFLINE"
+)
+
+// The states of High Availability (HA) of the cluster.
+const (
+	ClusterHAEnabled  = "ENABLED"
+	ClusterHADisabled = "DISABLED"
+	ClusterHADegraded = "DEGRADED"
+)
+
+// ClusterStatus is the status of vRealize Operations Manager cluster.
+type ClusterStatus struct {
+	// Name of the cluster.
+	Name string `json:"name,omitempty"`
+	// The online state of the cluster, e.g. ONLINE, OFFLINE, GOING_ONLINE.
+	OnlineState       string `json:"online_state,omitempty"`
+	OnlineStateReason string `json:"online_state_reason,omitempty"`
+	// Whether High Availability (HA) is enabled, and its state, i.e.
+	// ENABLED, DISABLED, DEGRADED.
+	HAEnabled bool   `json:"ha_enabled"`
+	HAState   string `json:"ha_state,omitempty"`
+	// The nodes (slices) of the cluster.
+	Nodes []*ClusterNode `json:"nodes,omitempty"`
+}
+
+// ClusterNode is a node (slice) of vRealize Operations Manager cluster.
+type ClusterNode struct {
+	// Identifier, name and address of the node.
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address,omitempty"`
+	// The role of the node, e.g. master, replica, data, remote_collector.
+	Role string `json:"role,omitempty"`
+	// Whether the node is the administration node.
+	IsAdmin bool `json:"is_admin"`
+	// The online state of the node, e.g. ONLINE, OFFLINE.
+	OnlineState       string `json:"online_state,omitempty"`
+	OnlineStateReason string `json:"online_state_reason,omitempty"`
+	// The health of the node, e.g. GREEN, YELLOW, RED.
+	Health string `json:"health,omitempty"`
+}
+
+// casaClusterInfo is the response of deployment/cluster/info CASA call.
+type casaClusterInfo struct {
+	Name      string `json:"cluster_name"`
+	HAEnabled bool   `json:"is_ha_enabled"`
+	HAState   string `json:"ha_state"`
+	Slices    []struct {
+		ID      string `json:"slice_uuid"`
+		Name    string `json:"slice_name"`
+		Address string `json:"slice_address"`
+		Role    string `json:"node_type"`
+		IsAdmin bool   `json:"is_admin_node"`
+	} `json:"slices"`
+}
+
+// casaOnlineState is the response of sysadmin/cluster/online_state CASA call.
+type casaOnlineState struct {
+	State  string `json:"cluster_online_state"`
+	Reason string `json:"cluster_online_state_reason"`
+	Slices []struct {
+		ID     string `json:"slice_uuid"`
+		State  string `json:"slice_online_state"`
+		Reason string `json:"slice_online_state_reason"`
+		Health string `json:"slice_health"`
+	} `json:"slice_online_state"`
+}
+
+// GetClusterStatus returns the status of the cluster and its nodes. The call
+// requires the credentials of the administrator of the cluster.
+func (c *Client) GetClusterStatus() (*ClusterStatus, error) {
+	b, err := c.requestCasa("GET", "deployment/cluster/info")
+	if err != nil {
+		return nil, err
+	}
+	info := &casaClusterInfo{}
+	if err := json.Unmarshal(b, info); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling cluster info: %s", err)
+	}
+
+	b, err = c.requestCasa("GET", "sysadmin/cluster/online_state")
+	if err != nil {
+		return nil, err
+	}
+	state := &casaOnlineState{}
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling cluster online state: %s", err)
+	}
+
+	status := &ClusterStatus{
+		Name:              info.Name,
+		OnlineState:       state.State,
+		OnlineStateReason: state.Reason,
+		HAEnabled:         info.HAEnabled,
+		HAState:           info.HAState,
+		Nodes:             []*ClusterNode{},
+	}
+	for _, slice := range info.Slices {
+		node := &ClusterNode{
+			ID:      slice.ID,
+			Name:    slice.Name,
+			Address: slice.Address,
+			Role:    slice.Role,
+			IsAdmin: slice.IsAdmin,
+		}
+		for _, sliceState := range state.Slices {
+			if sliceState.ID != slice.ID {
+				continue
+			}
+			node.OnlineState = sliceState.State
+			node.OnlineStateReason = sliceState.Reason
+			node.Health = sliceState.Health
+			break
+		}
+		status.Nodes = append(status.Nodes, node)
+	}
+	return status, nil
+}
+
+// Degraded returns true when the cluster is not online, any of its nodes
+// is not online or unhealthy, or its High Availability (HA) is degraded.
+// It also returns the reasons the cluster is degraded.
+func (s *ClusterStatus) Degraded() (bool, []string) {
+	reasons := []string{}
+	if s.OnlineState != ClusterOnline {
+		reasons = append(reasons, fmt.Sprintf("cluster %s is %s", s.Name, s.OnlineState))
+	}
+	if s.HAEnabled && s.HAState != "" && s.HAState != ClusterHAEnabled {
+		reasons = append(reasons, fmt.Sprintf("cluster %s high availability is %s", s.Name, s.HAState))
+	}
+	for _, node := range s.Nodes {
+		if node.OnlineState != ClusterOnline {
+			reasons = append(reasons, fmt.Sprintf("node %s is %s", node.Name, node.OnlineState))
+		}
+		switch node.Health {
+		case "", "GREEN":
+		default:
+			reasons = append(reasons, fmt.Sprintf("node %s health is %s", node.Name, node.Health))
+		}
+	}
+	return len(reasons) > 0, reasons
+}
+
+// ToJSONString serializes ClusterStatus to a string.
+func (s *ClusterStatus) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	. "github.com/greenpau/go-vrop/internal/server"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetClusterStatus(t *testing.T) {
+	testcases := []struct {
+		name          string
+		stateFileName string
+		degraded      bool
+		reasons       []string
+	}{
+		{
+			name:          "all nodes online",
+			stateFileName: "casa_online_state.json",
+			reasons:       []string{},
+		},
+		{
+			name:          "node offline and unhealthy",
+			stateFileName: "casa_online_state_degraded.json",
+			degraded:      true,
+			reasons: []string{
+				"node vrops-02 is OFFLINE",
+				"node vrops-02 health is RED",
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+				"/casa/deployment/cluster/info": []*MockTestEndpoint{
+					&MockTestEndpoint{FileName: "casa_cluster_info.json"},
+				},
+				"/casa/sysadmin/cluster/online_state": []*MockTestEndpoint{
+					&MockTestEndpoint{FileName: tc.stateFileName},
+				},
+			})
+			defer server.Close()
+			defer cli.Close()
+
+			status, err := cli.GetClusterStatus()
+			if err != nil {
+				t.Fatalf("failed getting cluster status: %s", err)
+			}
+			if len(status.Nodes) != 2 {
+				t.Fatalf("expected 2 nodes, got %d", len(status.Nodes))
+			}
+			if node := status.Nodes[0]; node.Name != "vrops-01" || !node.IsAdmin || node.OnlineState != ClusterOnline {
+				t.Fatalf("unexpected node: %+v", node)
+			}
+			degraded, reasons := status.Degraded()
+			if degraded != tc.degraded {
+				t.Fatalf("expected degraded %t, got %t: %v", tc.degraded, degraded, reasons)
+			}
+			if !reflect.DeepEqual(reasons, tc.reasons) {
+				t.Fatalf("unexpected reasons:\ngot:  %v\nwant: %v", reasons, tc.reasons)
+			}
+		})
+	}
+}
+
+func TestClusterStatusDegraded(t *testing.T) {
+	testcases := []struct {
+		name    string
+		status  *ClusterStatus
+		reasons []string
+	}{
+		{
+			name: "cluster offline",
+			status: &ClusterStatus{
+				Name:        "vrops-cluster",
+				OnlineState: ClusterOffline,
+			},
+			reasons: []string{"cluster vrops-cluster is OFFLINE"},
+		},
+		{
+			name: "high availability degraded",
+			status: &ClusterStatus{
+				Name:        "vrops-cluster",
+				OnlineState: ClusterOnline,
+				HAEnabled:   true,
+				HAState:     ClusterHADegraded,
+			},
+			reasons: []string{"cluster vrops-cluster high availability is DEGRADED"},
+		},
+		{
+			name: "high availability disabled",
+			status: &ClusterStatus{
+				Name:        "vrops-cluster",
+				OnlineState: ClusterOnline,
+				HAState:     ClusterHADisabled,
+			},
+			reasons: []string{},
+		},
+		{
+			name: "node health unknown",
+			status: &ClusterStatus{
+				Name:        "vrops-cluster",
+				OnlineState: ClusterOnline,
+				Nodes: []*ClusterNode{
+					&ClusterNode{Name: "vrops-01", OnlineState: ClusterOnline},
+					&ClusterNode{Name: "vrops-02", OnlineState: ClusterOnline, Health: "YELLOW"},
+				},
+			},
+			reasons: []string{"node vrops-02 health is YELLOW"},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			degraded, reasons := tc.status.Degraded()
+			if degraded != (len(tc.reasons) > 0) {
+				t.Fatalf("unexpected degraded %t: %v", degraded, reasons)
+			}
+			if !reflect.DeepEqual(reasons, tc.reasons) {
+				t.Fatalf("unexpected reasons:\ngot:  %v\nwant: %v", reasons, tc.reasons)
+			}
+		})
+	}
+}
+
+func TestRequestCasa(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/casa/deployment/cluster/info": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "casa_cluster_info.json"},
+		},
+		"/casa/sysadmin/cluster/online_state": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "POST", FileName: "casa_online_state.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	b, err := cli.requestCasa("GET", "deployment/cluster/info")
+	if err != nil {
+		t.Fatalf("failed making casa request: %s", err)
+	}
+	if !strings.Contains(string(b), "vrops-cluster") {
+		t.Fatalf("unexpected response: %s", b)
+	}
+
+	if _, err := cli.requestCasa("GET", "sysadmin/cluster/online_state"); err == nil {
+		t.Fatalf("expected error on non-200 response")
+	}
+
+	// The response exceeding the limit is refused rather than truncated.
+	cli.dataLimit = int64(len(b))
+	if _, err := cli.requestCasa("GET", "deployment/cluster/info"); err != nil {
+		t.Fatalf("expected the response at the limit to be accepted, got: %s", err)
+	}
+	cli.dataLimit = int64(len(b)) - 1
+	_, err = cli.requestCasa("GET", "deployment/cluster/info")
+	if err == nil || !strings.Contains(err.Error(), "response too large") {
+		t.Fatalf("expected response too large error, got: %v", err)
+	}
+}
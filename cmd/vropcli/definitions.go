@@ -0,0 +1,136 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/greenpau/go-vrop"
+	"io/ioutil"
+	"os"
+)
+
+// alertingConfig is the alerting configuration kept in a file.
+type alertingConfig struct {
+	SymptomDefinitions []*vrop.SymptomDefinition `json:"symptomDefinitions,omitempty"`
+	AlertDefinitions   []*vrop.AlertDefinition   `json:"alertDefinitions,omitempty"`
+}
+
+// exportAlertDefinitions writes symptom and alert definitions to a file.
+// When the file path is "-", the definitions are written to stdout.
+func exportAlertDefinitions(cli *vrop.Client, opts map[string]interface{}, fp string) error {
+	cfg := &alertingConfig{}
+	symptoms, err := cli.GetSymptomDefinitions(opts)
+	if err != nil {
+		return err
+	}
+	cfg.SymptomDefinitions = symptoms
+	alerts, err := cli.GetAlertDefinitions(opts)
+	if err != nil {
+		return err
+	}
+	cfg.AlertDefinitions = alerts
+	return writeJSONFile(fp, cfg)
+}
+
+// applyAlertDefinitions creates or updates symptom and alert definitions
+// found in a file. The symptom definitions are applied first, and the
+// references to them in alert definitions are updated when the server
+// assigns them new identifiers.
+func applyAlertDefinitions(cli *vrop.Client, fp string) error {
+	cfg := &alertingConfig{}
+	if err := readJSONFile(fp, cfg); err != nil {
+		return err
+	}
+
+	opts := make(map[string]interface{})
+	existingSymptoms, err := cli.GetSymptomDefinitions(opts)
+	if err != nil {
+		return err
+	}
+	symptomIDs := make(map[string]string)
+	for _, d := range cfg.SymptomDefinitions {
+		applied, changed, err := cli.ApplySymptomDefinition(d, existingSymptoms)
+		if err != nil {
+			return fmt.Errorf("failed applying symptom definition %s: %s", d.Name, err)
+		}
+		if d.ID != "" && d.ID != applied.ID {
+			symptomIDs[d.ID] = applied.ID
+		}
+		reportApplied("symptom definition", applied.Name, changed)
+	}
+
+	existingAlerts, err := cli.GetAlertDefinitions(opts)
+	if err != nil {
+		return err
+	}
+	for _, d := range cfg.AlertDefinitions {
+		for _, state := range d.States {
+			remapSymptomSet(state.SymptomSet, symptomIDs)
+		}
+		applied, changed, err := cli.ApplyAlertDefinition(d, existingAlerts)
+		if err != nil {
+			return fmt.Errorf("failed applying alert definition %s: %s", d.Name, err)
+		}
+		reportApplied("alert definition", applied.Name, changed)
+	}
+	return nil
+}
+
+func remapSymptomSet(set *vrop.SymptomSet, ids map[string]string) {
+	if set == nil {
+		return
+	}
+	for i, id := range set.SymptomDefinitionIDs {
+		if newID, exists := ids[id]; exists {
+			set.SymptomDefinitionIDs[i] = newID
+		}
+	}
+	for _, nested := range set.SymptomSets {
+		remapSymptomSet(nested, ids)
+	}
+}
+
+func reportApplied(kind, name string, changed bool) {
+	if changed {
+		fmt.Fprintf(os.Stderr, "applied %s: %s\n", kind, name)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "unchanged %s: %s\n", kind, name)
+}
+
+func writeJSONFile(fp string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed converting to json: %s", err)
+	}
+	b = append(b, '\n')
+	if fp == "-" {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(fp, b, 0644)
+}
+
+func readJSONFile(fp string, v interface{}) error {
+	b, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed unmarshalling %s: %s", fp, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/greenpau/go-vrop"
+	"reflect"
+	"testing"
+)
+
+func TestRemapSymptomSet(t *testing.T) {
+	set := &vrop.SymptomSet{
+		Type:     "SYMPTOM_SET_COMPOSITE",
+		Operator: "OR",
+		SymptomSets: []*vrop.SymptomSet{
+			&vrop.SymptomSet{
+				Type:                 "SYMPTOM_SET",
+				SymptomDefinitionIDs: []string{"SymptomDefinition-old-1", "SymptomDefinition-VMWARE-Builtin"},
+			},
+			&vrop.SymptomSet{
+				Type: "SYMPTOM_SET_COMPOSITE",
+				SymptomSets: []*vrop.SymptomSet{
+					&vrop.SymptomSet{
+						Type:                 "SYMPTOM_SET",
+						SymptomDefinitionIDs: []string{"SymptomDefinition-old-2"},
+					},
+				},
+			},
+		},
+	}
+	ids := map[string]string{
+		"SymptomDefinition-old-1": "SymptomDefinition-new-1",
+		"SymptomDefinition-old-2": "SymptomDefinition-new-2",
+	}
+
+	remapSymptomSet(set, ids)
+	remapSymptomSet(nil, ids)
+
+	got := set.SymptomSets[0].SymptomDefinitionIDs
+	want := []string{"SymptomDefinition-new-1", "SymptomDefinition-VMWARE-Builtin"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected symptom definition ids: got %v, want %v", got, want)
+	}
+	got = set.SymptomSets[1].SymptomSets[0].SymptomDefinitionIDs
+	want = []string{"SymptomDefinition-new-2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected nested symptom definition ids: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"github.com/greenpau/go-vrop"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"sort"
+)
+
+// groupsConfig is the membership of custom groups kept in a YAML file.
+type groupsConfig struct {
+	Groups []*groupConfig `yaml:"groups"`
+}
+
+type groupConfig struct {
+	Name    string          `yaml:"name"`
+	Type    string          `yaml:"type"`
+	Members []*memberConfig `yaml:"members"`
+	Exclude []*memberConfig `yaml:"exclude"`
+}
+
+type memberConfig struct {
+	Name    string `yaml:"name"`
+	Kind    string `yaml:"kind"`
+	Adapter string `yaml:"adapter"`
+}
+
+// syncGroups creates custom groups found in a YAML file and updates the
+// static membership of existing ones. The members are referenced by name
+// and resource kind.
+func syncGroups(cli *vrop.Client, fp string) error {
+	b, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return err
+	}
+	cfg := &groupsConfig{}
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return fmt.Errorf("failed unmarshalling %s: %s", fp, err)
+	}
+
+	existing, err := cli.GetCustomGroups()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range cfg.Groups {
+		if entry.Name == "" {
+			return fmt.Errorf("group name is empty in %s", fp)
+		}
+		if entry.Type == "" {
+			entry.Type = "Environment"
+		}
+		included, err := resolveMembers(cli, entry.Members)
+		if err != nil {
+			return fmt.Errorf("failed resolving members of group %s: %s", entry.Name, err)
+		}
+		excluded, err := resolveMembers(cli, entry.Exclude)
+		if err != nil {
+			return fmt.Errorf("failed resolving exclusions of group %s: %s", entry.Name, err)
+		}
+
+		var group *vrop.CustomGroup
+		for _, g := range existing {
+			if g.Key != nil && g.Key.Name == entry.Name && g.Key.ResourceKindKey == entry.Type {
+				group = g
+				break
+			}
+		}
+
+		if group == nil {
+			group = vrop.NewCustomGroup(entry.Name, entry.Type)
+			group.Membership.IncludedResources = included
+			group.Membership.ExcludedResources = excluded
+			if _, err := cli.CreateCustomGroup(group); err != nil {
+				return fmt.Errorf("failed creating group %s: %s", entry.Name, err)
+			}
+			reportApplied("group", entry.Name, true)
+			continue
+		}
+
+		if group.Membership == nil {
+			group.Membership = &vrop.GroupMembership{}
+		}
+		if equalStrings(group.Membership.IncludedResources, included) &&
+			equalStrings(group.Membership.ExcludedResources, excluded) {
+			reportApplied("group", entry.Name, false)
+			continue
+		}
+		group.Membership.IncludedResources = included
+		group.Membership.ExcludedResources = excluded
+		if _, err := cli.UpdateCustomGroup(group); err != nil {
+			return fmt.Errorf("failed updating group %s: %s", entry.Name, err)
+		}
+		reportApplied("group", entry.Name, true)
+	}
+	return nil
+}
+
+// resolveMembers returns the sorted identifiers of the resources
+// with the provided names and kinds.
+func resolveMembers(cli *vrop.Client, members []*memberConfig) ([]string, error) {
+	var ids []string
+	for _, member := range members {
+		if member.Name == "" || member.Kind == "" {
+			return nil, fmt.Errorf("member name or kind is empty")
+		}
+		opts := map[string]interface{}{
+			"name":          member.Name,
+			"resource_kind": member.Kind,
+		}
+		if member.Adapter != "" {
+			opts["adapter_kind"] = member.Adapter
+		}
+		resources, err := cli.GetResources(opts)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, r := range resources {
+			if r.Key != nil && r.Key.Name == member.Name {
+				ids = append(ids, r.ID)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s %s not found", member.Kind, member.Name)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x := append([]string{}, a...)
+	y := append([]string{}, b...)
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
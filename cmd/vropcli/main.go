@@ -15,14 +15,18 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"github.com/greenpau/go-vrop"
 	"github.com/greenpau/versioned"
 	"github.com/spf13/viper"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 var (
@@ -52,6 +56,36 @@ func main() {
 	var configFile string
 	var host, username, password string
 	var getVirtualMachines bool
+	var getHostSystems, getClusters, getDatastores, getDatacenters bool
+	var getVCenterInstances bool
+	var getAlerts, acknowledgeAlerts, acknowledgeAllAlerts, activeAlertsOnly bool
+	var alertCriticality string
+	var exportAlertDefinitionsFile, applyAlertDefinitionsFile string
+	var adapterKind, resourceKind string
+	var alertDetailID string
+	var startMaintenance, stopMaintenance bool
+	var resourceIDs string
+	var maintenanceDuration time.Duration
+	var getGroups bool
+	var groupMembersID, syncGroupsFile string
+	var getTags, getResources bool
+	var assignTags, unassignTags, tagFilter string
+	var getPolicies, getPolicyAssignments bool
+	var exportPolicyID, importPolicyFile, outputFile string
+	var exportSuperMetricsFile, applySuperMetricsFile string
+	var getReportDefinitions bool
+	var generateReportID, reportResourceID, reportFormat string
+	var reportTimeout time.Duration
+	var policyTimeout time.Duration
+	var overwritePolicy bool
+	var getAdapterInstances bool
+	var startCollectionID, stopCollectionID, testConnectionID string
+	var getCollectors, getCollectorGroups, getStaleCollectors bool
+	var maxHeartbeatAge time.Duration
+	var getClusterStatus, getServerVersion bool
+	var lenientDecoding bool
+	var getResourceSummaries bool
+	var summaryFormat string
 
 	flag.StringVar(&configFile, "config", "", "configuration file")
 	flag.StringVar(&host, "host", "", "vRealize Operations Manager Hostname")
@@ -59,7 +93,61 @@ func main() {
 	flag.StringVar(&password, "password", "", "Password")
 
 	flag.BoolVar(&getVirtualMachines, "get-virtual-machines", false, "Get virtual machines")
+	flag.BoolVar(&getHostSystems, "get-host-systems", false, "Get ESXi hosts")
+	flag.BoolVar(&getClusters, "get-clusters", false, "Get compute clusters")
+	flag.BoolVar(&getDatastores, "get-datastores", false, "Get datastores")
+	flag.BoolVar(&getDatacenters, "get-datacenters", false, "Get datacenters")
+	flag.BoolVar(&getVCenterInstances, "get-vcenter-instances", false, "Get vCenter adapter instances")
+	flag.BoolVar(&getAlerts, "get-alerts", false, "Get alerts")
+	flag.BoolVar(&acknowledgeAlerts, "acknowledge-alerts", false, "Acknowledge active alerts, filtered by -alert-criticality, or all with -all-alerts")
+	flag.BoolVar(&acknowledgeAllAlerts, "all-alerts", false, "Acknowledge all active alerts, when no filter is provided")
+	flag.BoolVar(&activeAlertsOnly, "active-only", false, "Select active alerts only")
+	flag.StringVar(&exportAlertDefinitionsFile, "export-alert-definitions", "", "Export symptom and alert definitions to a file, or - for stdout")
+	flag.StringVar(&applyAlertDefinitionsFile, "apply-alert-definitions", "", "Apply symptom and alert definitions from a file")
+	flag.StringVar(&adapterKind, "adapter-kind", "", "Filter by adapter kind, e.g. VMWARE")
+	flag.StringVar(&resourceKind, "resource-kind", "", "Filter by resource kind, e.g. VirtualMachine")
+	flag.StringVar(&alertDetailID, "get-alert-detail", "", "Get the alert with the provided id, its symptoms and recommendations")
+	flag.BoolVar(&startMaintenance, "start-maintenance", false, "Put resources in maintenance")
+	flag.BoolVar(&stopMaintenance, "stop-maintenance", false, "Take resources out of maintenance")
+	flag.StringVar(&resourceIDs, "resource-ids", "", "Comma-separated resource ids")
+	flag.DurationVar(&maintenanceDuration, "duration", 0, "Maintenance duration, e.g. 2h; zero means until stopped")
+	flag.BoolVar(&getGroups, "get-groups", false, "Get custom groups")
+	flag.StringVar(&groupMembersID, "get-group-members", "", "Get the members of the custom group with the provided id")
+	flag.StringVar(&syncGroupsFile, "sync-groups", "", "Sync custom group membership from a YAML file")
+	flag.BoolVar(&getTags, "get-tags", false, "Get tags")
+	flag.BoolVar(&getResources, "get-resources", false, "Get resources, filtered by -adapter-kind, -resource-kind, and -tags")
+	flag.StringVar(&tagFilter, "tags", "", "Comma-separated tags in category:name format")
+	flag.StringVar(&assignTags, "assign-tags", "", "Assign comma-separated tags in category:name format to -resource-ids")
+	flag.StringVar(&unassignTags, "unassign-tags", "", "Unassign comma-separated tags in category:name format from -resource-ids")
+	flag.BoolVar(&getPolicies, "get-policies", false, "Get policies")
+	flag.BoolVar(&getPolicyAssignments, "get-policy-assignments", false, "Get policies applied to custom groups")
+	flag.StringVar(&exportPolicyID, "export-policy", "", "Export the policy with the provided id to -output")
+	flag.StringVar(&importPolicyFile, "import-policy", "", "Import the policy from an archive file")
+	flag.BoolVar(&overwritePolicy, "overwrite-policy", false, "Overwrite the existing policy with the same name on -import-policy")
+	flag.DurationVar(&policyTimeout, "policy-timeout", 10*time.Minute, "The maximum time to export or import the policy")
+	flag.StringVar(&outputFile, "output", "-", "Output file, or - for stdout")
+	flag.StringVar(&exportSuperMetricsFile, "export-super-metrics", "", "Export super metrics to a file, or - for stdout")
+	flag.StringVar(&applySuperMetricsFile, "apply-super-metrics", "", "Apply super metrics from a file")
+	flag.BoolVar(&getReportDefinitions, "get-report-definitions", false, "Get report definitions")
+	flag.StringVar(&generateReportID, "generate-report", "", "Generate the report with the provided definition id and download it to -output")
+	flag.StringVar(&reportResourceID, "report-resource-id", "", "The id of the resource the report is generated for")
+	flag.StringVar(&reportFormat, "report-format", "PDF", "The format of the downloaded report, i.e. PDF or CSV")
+	flag.DurationVar(&reportTimeout, "report-timeout", 30*time.Minute, "The maximum time to wait for the report and to download it")
+	flag.BoolVar(&getAdapterInstances, "get-adapter-instances", false, "Get adapter instances, filtered by -adapter-kind")
+	flag.StringVar(&startCollectionID, "start-collection", "", "Start collection by the adapter instance with the provided id")
+	flag.StringVar(&stopCollectionID, "stop-collection", "", "Stop collection by the adapter instance with the provided id")
+	flag.StringVar(&testConnectionID, "test-connection", "", "Test connection of the adapter instance with the provided id")
+	flag.BoolVar(&getCollectors, "get-collectors", false, "Get collectors")
+	flag.BoolVar(&getCollectorGroups, "get-collector-groups", false, "Get collector groups")
+	flag.BoolVar(&getStaleCollectors, "get-stale-collectors", false, "Get collectors with stale heartbeat; exits non-zero when found")
+	flag.DurationVar(&maxHeartbeatAge, "max-heartbeat-age", 5*time.Minute, "The age of collector heartbeat considered stale")
+	flag.BoolVar(&getClusterStatus, "cluster-status", false, "Get the status of the cluster; exits non-zero when it is degraded")
+	flag.BoolVar(&getServerVersion, "get-server-version", false, "Get the version of the server")
+	flag.StringVar(&alertCriticality, "alert-criticality", "", "Comma-separated alert criticality levels, e.g. CRITICAL,IMMEDIATE")
 
+	flag.BoolVar(&getResourceSummaries, "get-resource-summaries", false, "Get resource summaries to -output, filtered by -adapter-kind, -resource-kind, and -tags")
+	flag.StringVar(&summaryFormat, "summary-format", "json", "The format of resource summaries, i.e. json or csv")
+	flag.BoolVar(&lenientDecoding, "lenient", false, "Tolerate unsupported keys in API responses, e.g. fields added by newer versions")
 	flag.StringVar(&logLevel, "log-level", "info", "logging severity level")
 	flag.BoolVar(&isShowVersion, "version", false, "show version")
 
@@ -166,6 +254,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	if lenientDecoding {
+		if err := cli.SetLenientDecoding(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+	}
+
 	cli.Info()
 
 	opts = make(map[string]interface{})
@@ -176,12 +271,462 @@ func main() {
 			os.Exit(1)
 		}
 		for _, item := range items {
-			s, err := item.ToJSONString()
+			printJSONString(item)
+		}
+		os.Exit(0)
+	}
+
+	if getHostSystems {
+		items, err := cli.GetHostSystems(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSONString(item)
+		}
+		os.Exit(0)
+	}
+
+	if getClusters {
+		items, err := cli.GetClusterComputeResources(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSONString(item)
+		}
+		os.Exit(0)
+	}
+
+	if getDatastores {
+		items, err := cli.GetDatastores(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSONString(item)
+		}
+		os.Exit(0)
+	}
+
+	if getDatacenters {
+		items, err := cli.GetDatacenters(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSONString(item)
+		}
+		os.Exit(0)
+	}
+
+	if getVCenterInstances {
+		items, err := cli.GetVCenterInstances(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSONString(item)
+		}
+		os.Exit(0)
+	}
+
+	if getAlerts || acknowledgeAlerts {
+		if acknowledgeAlerts && alertCriticality == "" && !acknowledgeAllAlerts {
+			fmt.Fprintf(os.Stderr, "refusing to acknowledge all active alerts: provide -alert-criticality, or -all-alerts\n")
+			os.Exit(1)
+		}
+		filter := &vrop.AlertFilter{
+			ActiveOnly: activeAlertsOnly || acknowledgeAlerts,
+		}
+		if alertCriticality != "" {
+			for _, level := range strings.Split(alertCriticality, ",") {
+				filter.Criticality = append(filter.Criticality, strings.ToUpper(strings.TrimSpace(level)))
+			}
+		}
+		items, err := cli.GetAlerts(filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		if getAlerts {
+			for _, item := range items {
+				printJSONString(item)
+			}
+		}
+		if acknowledgeAlerts {
+			var ids []string
+			for _, item := range items {
+				ids = append(ids, item.ID)
+			}
+			if len(ids) > 0 {
+				if err := cli.AcknowledgeAlerts(ids); err != nil {
+					fmt.Fprintf(os.Stderr, "%s\n", err)
+					os.Exit(1)
+				}
+			}
+			fmt.Fprintf(os.Stderr, "acknowledged %d alerts\n", len(ids))
+		}
+		os.Exit(0)
+	}
+
+	if alertDetailID != "" {
+		item, err := cli.GetAlertDetail(alertDetailID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		printJSONString(item)
+		os.Exit(0)
+	}
+
+	if startMaintenance || stopMaintenance {
+		ids := splitList(resourceIDs)
+		if startMaintenance {
+			err = cli.MarkMaintenance(ids, maintenanceDuration)
+		} else {
+			err = cli.UnmarkMaintenance(ids)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if getGroups {
+		items, err := cli.GetCustomGroups()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSONString(item)
+		}
+		os.Exit(0)
+	}
+
+	if groupMembersID != "" {
+		items, err := cli.GetCustomGroupMembers(groupMembersID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSON(item)
+		}
+		os.Exit(0)
+	}
+
+	if getTags {
+		items, err := cli.GetTags()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSON(item)
+		}
+		os.Exit(0)
+	}
+
+	if getResources || getResourceSummaries {
+		if adapterKind != "" {
+			opts["adapter_kind"] = adapterKind
+		}
+		if resourceKind != "" {
+			opts["resource_kind"] = resourceKind
+		}
+		if tagFilter != "" {
+			tags, err := parseTags(tagFilter)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s\n", err)
+				os.Exit(1)
+			}
+			opts["tags"] = tags
+		}
+		if getResourceSummaries {
+			if summaryFormat != "json" && summaryFormat != "csv" {
+				fmt.Fprintf(os.Stderr, "unsupported summary format: %s\n", summaryFormat)
+				os.Exit(1)
+			}
+			items, err := cli.GetResourceSummaries(opts)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "%s\n", err)
-				continue
+				os.Exit(1)
 			}
-			fmt.Fprintf(os.Stdout, "%s\n", s)
+			if err := withOutput(outputFile, func(w io.Writer) error {
+				if summaryFormat == "csv" {
+					return vrop.WriteResourceSummariesCSV(w, items)
+				}
+				for _, item := range items {
+					s, err := item.ToJSONString()
+					if err != nil {
+						return err
+					}
+					fmt.Fprintf(w, "%s\n", s)
+				}
+				return nil
+			}); err != nil {
+				fmt.Fprintf(os.Stderr, "%s\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+		items, err := cli.GetResources(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSON(item)
+		}
+		os.Exit(0)
+	}
+
+	if assignTags != "" || unassignTags != "" {
+		ids := splitList(resourceIDs)
+		if assignTags != "" {
+			tags, err := parseTags(assignTags)
+			if err == nil {
+				err = cli.AssignTags(ids, tags)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s\n", err)
+				os.Exit(1)
+			}
+		}
+		if unassignTags != "" {
+			tags, err := parseTags(unassignTags)
+			if err == nil {
+				err = cli.UnassignTags(ids, tags)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s\n", err)
+				os.Exit(1)
+			}
+		}
+		os.Exit(0)
+	}
+
+	if syncGroupsFile != "" {
+		if err := syncGroups(cli, syncGroupsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if getPolicies {
+		items, err := cli.GetPolicies()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSONString(item)
+		}
+		os.Exit(0)
+	}
+
+	if getPolicyAssignments {
+		items, err := cli.GetPolicyAssignments()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSON(item)
+		}
+		os.Exit(0)
+	}
+
+	if exportPolicyID != "" {
+		if err := withOutput(outputFile, func(w io.Writer) error {
+			ctx, cancel := context.WithTimeout(context.Background(), policyTimeout)
+			defer cancel()
+			return cli.ExportPolicy(ctx, exportPolicyID, w)
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if importPolicyFile != "" {
+		fh, err := os.Open(importPolicyFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), policyTimeout)
+		err = cli.ImportPolicy(ctx, fh, overwritePolicy)
+		cancel()
+		fh.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if exportSuperMetricsFile != "" {
+		if err := exportSuperMetrics(cli, exportSuperMetricsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if applySuperMetricsFile != "" {
+		if err := applySuperMetrics(cli, applySuperMetricsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if getReportDefinitions {
+		items, err := cli.GetReportDefinitions()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSON(item)
+		}
+		os.Exit(0)
+	}
+
+	if generateReportID != "" {
+		report, err := cli.GenerateReport(generateReportID, reportResourceID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+		defer cancel()
+		report, err = cli.WaitForReport(ctx, report.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		if err := withOutput(outputFile, func(w io.Writer) error {
+			return cli.DownloadReport(ctx, report.ID, reportFormat, w)
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if getAdapterInstances {
+		items, err := cli.GetAdapterInstances(adapterKind)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSONString(item)
+		}
+		os.Exit(0)
+	}
+
+	if startCollectionID != "" || stopCollectionID != "" || testConnectionID != "" {
+		switch {
+		case startCollectionID != "":
+			err = cli.StartAdapterInstance(startCollectionID)
+		case stopCollectionID != "":
+			err = cli.StopAdapterInstance(stopCollectionID)
+		default:
+			err = cli.TestAdapterInstanceConnection(testConnectionID)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if getCollectors || getStaleCollectors {
+		items, err := cli.GetCollectors()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		if getStaleCollectors {
+			items = vrop.StaleCollectors(items, maxHeartbeatAge)
+		}
+		for _, item := range items {
+			printJSONString(item)
+		}
+		if getStaleCollectors && len(items) > 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if getCollectorGroups {
+		items, err := cli.GetCollectorGroups()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		for _, item := range items {
+			printJSON(item)
+		}
+		os.Exit(0)
+	}
+
+	if getServerVersion {
+		v, err := cli.GetVersion()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		printJSON(v)
+		os.Exit(0)
+	}
+
+	if getClusterStatus {
+		status, err := cli.GetClusterStatus()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		printJSONString(status)
+		if degraded, reasons := status.Degraded(); degraded {
+			for _, reason := range reasons {
+				fmt.Fprintf(os.Stderr, "%s\n", reason)
+			}
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if exportAlertDefinitionsFile != "" {
+		if adapterKind != "" {
+			opts["adapter_kind"] = adapterKind
+		}
+		if resourceKind != "" {
+			opts["resource_kind"] = resourceKind
+		}
+		if err := exportAlertDefinitions(cli, opts, exportAlertDefinitionsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if applyAlertDefinitionsFile != "" {
+		if err := applyAlertDefinitions(cli, applyAlertDefinitionsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
 		}
 		os.Exit(0)
 	}
@@ -189,3 +734,65 @@ func main() {
 	fmt.Fprintf(os.Stderr, "actionable argument is missing\n")
 	os.Exit(1)
 }
+
+type jsonStringer interface {
+	ToJSONString() (string, error)
+}
+
+func printJSONString(item jsonStringer) {
+	s, err := item.ToJSONString()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stdout, "%s\n", s)
+}
+
+func printJSON(item interface{}) {
+	b, err := json.Marshal(item)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stdout, "%s\n", b)
+}
+
+// splitList splits comma-separated list and drops empty entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseTags(s string) ([]*vrop.Tag, error) {
+	var tags []*vrop.Tag
+	for _, item := range splitList(s) {
+		tag, err := vrop.ParseTag(item)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// withOutput calls the function with the writer to the output file.
+// When the file path is "-", the writer is stdout.
+func withOutput(fp string, fn func(io.Writer) error) error {
+	if fp == "-" || fp == "" {
+		return fn(os.Stdout)
+	}
+	fh, err := os.Create(fp)
+	if err != nil {
+		return err
+	}
+	if err := fn(fh); err != nil {
+		fh.Close()
+		return err
+	}
+	return fh.Close()
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"github.com/greenpau/go-vrop"
+)
+
+// superMetricsConfig is the super metrics configuration kept in a file.
+type superMetricsConfig struct {
+	SuperMetrics []*superMetricConfig `json:"superMetrics,omitempty"`
+}
+
+// superMetricConfig is a super metric with the resource kinds it is
+// assigned to and the policies it is enabled in.
+type superMetricConfig struct {
+	*vrop.SuperMetric
+	ResourceKinds []*vrop.ResourceKindRef `json:"resourceKinds,omitempty"`
+	PolicyIDs     []string                `json:"policyIds,omitempty"`
+}
+
+// exportSuperMetrics writes super metrics to a file. When the file
+// path is "-", the super metrics are written to stdout.
+func exportSuperMetrics(cli *vrop.Client, fp string) error {
+	metrics, err := cli.GetSuperMetrics()
+	if err != nil {
+		return err
+	}
+	cfg := &superMetricsConfig{}
+	for _, m := range metrics {
+		cfg.SuperMetrics = append(cfg.SuperMetrics, &superMetricConfig{SuperMetric: m})
+	}
+	return writeJSONFile(fp, cfg)
+}
+
+// applySuperMetrics creates or updates super metrics found in a file,
+// and assigns them to resource kinds and enables them in policies,
+// when the file lists them.
+func applySuperMetrics(cli *vrop.Client, fp string) error {
+	cfg := &superMetricsConfig{}
+	if err := readJSONFile(fp, cfg); err != nil {
+		return err
+	}
+	existing, err := cli.GetSuperMetrics()
+	if err != nil {
+		return err
+	}
+	for _, entry := range cfg.SuperMetrics {
+		if entry.SuperMetric == nil {
+			continue
+		}
+		applied, changed, err := cli.ApplySuperMetric(entry.SuperMetric, existing)
+		if err != nil {
+			return fmt.Errorf("failed applying super metric %s: %s", entry.Name, err)
+		}
+		reportApplied("super metric", applied.Name, changed)
+		if len(entry.ResourceKinds) > 0 {
+			if err := cli.AssignSuperMetric(applied.ID, entry.ResourceKinds); err != nil {
+				return err
+			}
+		}
+		if len(entry.PolicyIDs) > 0 {
+			if err := cli.EnableSuperMetric(applied.ID, entry.PolicyIDs, entry.ResourceKinds); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Collector is a node collecting data, e.g. a remote collector.
+type Collector struct {
+	// Identifier of the Collector.
+	ID int `json:"id"`
+	// UUID and name of the Collector.
+	UUID string `json:"uuId,omitempty"`
+	Name string `json:"name,omitempty"`
+	// The state of the Collector, e.g. UP or DOWN.
+	State string `json:"state,omitempty"`
+	// The host name or IP address of the Collector.
+	HostName string `json:"hostName,omitempty"`
+	// Whether the Collector runs on a cluster node.
+	IsLocal bool `json:"local,omitempty"`
+	// The time of the last heartbeat of the Collector.
+	LastHeartbeat Timestamp `json:"lastHeartbeat"`
+	// The adapter instances run by the Collector.
+	AdapterInstances []*AdapterInstance `json:"adapterInstances,omitempty"`
+}
+
+// CollectorGroup is a group of collectors.
+type CollectorGroup struct {
+	// Identifier of the CollectorGroup.
+	ID string `json:"id,omitempty"`
+	// Name and description of the CollectorGroup.
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// The identifiers of the collectors in the CollectorGroup.
+	CollectorIDs []int `json:"collectorId,omitempty"`
+}
+
+// CollectorsResponse is a response with collectors.
+type CollectorsResponse struct {
+	Collectors []*Collector `json:"collector,omitempty"`
+}
+
+// CollectorGroupsResponse is a response with collector groups.
+type CollectorGroupsResponse struct {
+	CollectorGroups []*CollectorGroup `json:"collectorGroups,omitempty"`
+}
+
+// GetCollectors returns a list of collectors with the adapter instances
+// each collector runs.
+func (c *Client) GetCollectors() ([]*Collector, error) {
+	collectors := []*Collector{}
+	if err := c.authenticate(); err != nil {
+		return collectors, err
+	}
+	params := make(map[string]string)
+	b, err := c.request("GET", "collectors", params)
+	if err != nil {
+		return collectors, err
+	}
+	resp := &CollectorsResponse{}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return collectors, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	collectors = append(collectors, resp.Collectors...)
+
+	instances, err := c.GetAdapterInstances("")
+	if err != nil {
+		return collectors, err
+	}
+	for _, collector := range collectors {
+		for _, instance := range instances {
+			if instance.CollectorID == collector.ID {
+				collector.AdapterInstances = append(collector.AdapterInstances, instance)
+			}
+		}
+	}
+	return collectors, nil
+}
+
+// GetCollectorGroups returns a list of collector groups.
+func (c *Client) GetCollectorGroups() ([]*CollectorGroup, error) {
+	groups := []*CollectorGroup{}
+	if err := c.authenticate(); err != nil {
+		return groups, err
+	}
+	if err := c.requireCapability(CapabilityCollectorGroups); err != nil {
+		return groups, err
+	}
+	params := make(map[string]string)
+	b, err := c.request("GET", "collectorgroups", params)
+	if err != nil {
+		return groups, err
+	}
+	resp := &CollectorGroupsResponse{}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return groups, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	groups = append(groups, resp.CollectorGroups...)
+	return groups, nil
+}
+
+// IsStale returns true when the collector has not sent a heartbeat within
+// the provided duration, or it is not up.
+func (c *Collector) IsStale(maxAge time.Duration) bool {
+	return c.isStale(time.Now(), maxAge)
+}
+
+// isStale returns true when the collector is not up or, at the provided
+// time, its last heartbeat is older than the provided duration.
+func (c *Collector) isStale(now time.Time, maxAge time.Duration) bool {
+	if c.State != "" && c.State != "UP" {
+		return true
+	}
+	if c.LastHeartbeat.IsZero() {
+		return true
+	}
+	return now.Sub(c.LastHeartbeat.Time) > maxAge
+}
+
+// StaleCollectors returns the collectors that have not sent a heartbeat
+// within the provided duration, or are not up.
+func StaleCollectors(collectors []*Collector, maxAge time.Duration) []*Collector {
+	stale := []*Collector{}
+	for _, c := range collectors {
+		if c.IsStale(maxAge) {
+			stale = append(stale, c)
+		}
+	}
+	return stale
+}
+
+// ToJSONString serializes Collector to a string.
+func (c *Collector) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	. "github.com/greenpau/go-vrop/internal/server"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetCollectors(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/collectors": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "collectors.json"},
+		},
+		"/suite-api/api/adapters": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "adapters.json"},
+		},
+		"/suite-api/api/resources": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "resources_adapter_instances.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	collectors, err := cli.GetCollectors()
+	if err != nil {
+		t.Fatalf("failed getting collectors: %s", err)
+	}
+	if len(collectors) != 2 {
+		t.Fatalf("expected 2 collectors, got %d", len(collectors))
+	}
+	local, remote := collectors[0], collectors[1]
+	if local.ID != 1 || !local.IsLocal || local.State != "UP" || local.LastHeartbeat.IsZero() {
+		t.Fatalf("unexpected collector: %+v", local)
+	}
+	// The adapter instances are attached to the collector running them.
+	var ids []string
+	for _, instance := range local.AdapterInstances {
+		ids = append(ids, instance.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"a1b2c3d4-0001", "a1b2c3d4-0002"}) {
+		t.Fatalf("unexpected adapter instances of collector %d: %v", local.ID, ids)
+	}
+	if remote.IsLocal || len(remote.AdapterInstances) != 0 {
+		t.Fatalf("unexpected collector: %+v", remote)
+	}
+
+	stale := StaleCollectors(collectors, 24*time.Hour)
+	if len(stale) != 2 {
+		t.Fatalf("expected 2 stale collectors, got %d", len(stale))
+	}
+}
+
+func TestGetCollectorGroups(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/versions/current": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "versions_current.json"},
+		},
+		"/suite-api/api/collectorgroups": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "collector_groups.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	groups, err := cli.GetCollectorGroups()
+	if err != nil {
+		t.Fatalf("failed getting collector groups: %s", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 collector group, got %d", len(groups))
+	}
+	g := groups[0]
+	if g.Name != "dc2-collectors" || !reflect.DeepEqual(g.CollectorIDs, []int{2}) {
+		t.Fatalf("unexpected collector group: %+v", g)
+	}
+}
+
+func TestCollectorIsStale(t *testing.T) {
+	now := time.Date(2020, 12, 11, 18, 0, 0, 0, time.UTC)
+	maxAge := 10 * time.Minute
+	testcases := []struct {
+		name      string
+		state     string
+		heartbeat time.Time
+		stale     bool
+	}{
+		{name: "recent heartbeat", state: "UP", heartbeat: now.Add(-time.Minute)},
+		{name: "heartbeat at threshold", state: "UP", heartbeat: now.Add(-maxAge)},
+		{name: "heartbeat past threshold", state: "UP", heartbeat: now.Add(-maxAge - time.Millisecond), stale: true},
+		{name: "heartbeat without state", heartbeat: now.Add(-time.Minute)},
+		{name: "collector down", state: "DOWN", heartbeat: now.Add(-time.Minute), stale: true},
+		{name: "no heartbeat", state: "UP", stale: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Collector{State: tc.state, LastHeartbeat: NewTimestamp(tc.heartbeat)}
+			if got := c.isStale(now, maxAge); got != tc.stale {
+				t.Fatalf("expected stale %t, got %t", tc.stale, got)
+			}
+		})
+	}
+}
+
+func TestStaleCollectors(t *testing.T) {
+	collectors := []*Collector{
+		&Collector{ID: 1, State: "UP", LastHeartbeat: NewTimestamp(time.Now())},
+		&Collector{ID: 2, State: "UP", LastHeartbeat: NewTimestamp(time.Now().Add(-time.Hour))},
+		&Collector{ID: 3, State: "DOWN", LastHeartbeat: NewTimestamp(time.Now())},
+	}
+	var ids []int
+	for _, c := range StaleCollectors(collectors, 10*time.Minute) {
+		ids = append(ids, c.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{2, 3}) {
+		t.Fatalf("unexpected stale collectors: %v", ids)
+	}
+	if stale := StaleCollectors(nil, time.Minute); len(stale) != 0 {
+		t.Fatalf("unexpected stale collectors: %v", stale)
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// CustomGroupAdapterKind is the adapter kind of custom groups.
+const CustomGroupAdapterKind = "Container"
+
+// CustomGroup is a group of resources defined by a user.
+type CustomGroup struct {
+	// Identifier of the CustomGroup, assigned by the server.
+	ID string `json:"id,omitempty"`
+	// Resource key of the CustomGroup.
+	Key *CustomGroupKey `json:"resourceKey"`
+	// Whether the membership is re-evaluated automatically.
+	AutoResolveMembership bool `json:"autoResolveMembership"`
+	// The members of the CustomGroup.
+	Membership *GroupMembership `json:"membershipDefinition,omitempty"`
+	// Identifier of the policy applied to the CustomGroup.
+	PolicyID string `json:"policy,omitempty"`
+}
+
+// CustomGroupKey is a key of a custom group.
+type CustomGroupKey struct {
+	// Name of the group.
+	Name string `json:"name"`
+	// Adapter Kind of the group, i.e. Container.
+	AdapterKindKey string `json:"adapterKindKey"`
+	// The type of the group, e.g. Environment, Function, Location.
+	ResourceKindKey string `json:"resourceKindKey"`
+}
+
+// GroupMembership is the definition of the members of a custom group.
+type GroupMembership struct {
+	// The identifiers of the resources always in the group.
+	IncludedResources []string `json:"includedResources,omitempty"`
+	// The identifiers of the resources never in the group.
+	ExcludedResources []string `json:"excludedResources,omitempty"`
+	// The rules selecting the members of the group dynamically.
+	Rules []*GroupMembershipRule `json:"rules,omitempty"`
+}
+
+// GroupMembershipRule is a rule selecting the resources of a particular
+// kind. A resource matches the rule when it matches all of the conditions.
+type GroupMembershipRule struct {
+	ResourceKind               *GroupResourceKind    `json:"resourceKindKey,omitempty"`
+	StatConditionRules         []*GroupConditionRule `json:"statConditionRules,omitempty"`
+	PropertyConditionRules     []*GroupConditionRule `json:"propertyConditionRules,omitempty"`
+	ResourceNameConditionRules []*GroupConditionRule `json:"resourceNameConditionRules,omitempty"`
+	RelationshipConditionRules []*GroupConditionRule `json:"relationshipConditionRules,omitempty"`
+	ResourceTagConditionRules  []*GroupConditionRule `json:"resourceTagConditionRules,omitempty"`
+}
+
+// GroupResourceKind is the kind of the resources selected by a rule.
+type GroupResourceKind struct {
+	ResourceKind string `json:"resourceKind"`
+	AdapterKind  string `json:"adapterKind"`
+}
+
+// GroupConditionRule is a condition of a membership rule.
+type GroupConditionRule struct {
+	// The metric or property key, for metric and property conditions.
+	Key string `json:"key,omitempty"`
+	// The name of the resource, for name and relationship conditions.
+	Name string `json:"name,omitempty"`
+	// The relationship, e.g. PARENT, CHILD, DESCENDANT, for
+	// relationship conditions.
+	Relation string `json:"relation,omitempty"`
+	// The tag category, for tag conditions.
+	Category string `json:"category,omitempty"`
+	// The value the resource is compared with.
+	StringValue string  `json:"stringValue,omitempty"`
+	DoubleValue float64 `json:"doubleValue,omitempty"`
+	// The operator, e.g. EQ, NOT_EQ, CONTAINS, STARTS_WITH, GT, LT.
+	CompareOperator string `json:"compareOperator,omitempty"`
+}
+
+// CustomGroupsResponse is a response with custom groups.
+type CustomGroupsResponse struct {
+	Page   *PageInfo      `json:"pageInfo,omitempty"`
+	Links  []*Link        `json:"links,omitempty"`
+	Groups []*CustomGroup `json:"groups,omitempty"`
+}
+
+// NewCustomGroup returns an instance of CustomGroup of the provided type,
+// e.g. Environment.
+func NewCustomGroup(name, groupType string) *CustomGroup {
+	return &CustomGroup{
+		Key: &CustomGroupKey{
+			Name:            name,
+			AdapterKindKey:  CustomGroupAdapterKind,
+			ResourceKindKey: groupType,
+		},
+		Membership: &GroupMembership{},
+	}
+}
+
+// GetCustomGroups returns a list of custom groups.
+func (c *Client) GetCustomGroups() ([]*CustomGroup, error) {
+	groups := []*CustomGroup{}
+	if err := c.authenticate(); err != nil {
+		return groups, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := make(map[string]string)
+		params["page"] = strconv.Itoa(pageOffset)
+		params["pageSize"] = strconv.Itoa(pageSize)
+		b, err := c.request("GET", "resources/groups", params)
+		if err != nil {
+			return groups, err
+		}
+
+		resp := &CustomGroupsResponse{}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return groups, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+
+		groups = append(groups, resp.Groups...)
+
+		if len(resp.Groups) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return groups, nil
+}
+
+// GetCustomGroup returns the custom group with the provided identifier.
+func (c *Client) GetCustomGroup(id string) (*CustomGroup, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty custom group id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.request("GET", "resources/groups/"+id, params)
+	if err != nil {
+		return nil, err
+	}
+	g := &CustomGroup{}
+	if err := json.Unmarshal(b, g); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return g, nil
+}
+
+// CreateCustomGroup creates the custom group and returns it with the
+// identifier assigned by the server.
+func (c *Client) CreateCustomGroup(g *CustomGroup) (*CustomGroup, error) {
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	payload := *g
+	payload.ID = ""
+	params := make(map[string]string)
+	b, err := c.requestWithPayload("POST", "resources/groups", params, &payload)
+	if err != nil {
+		return nil, err
+	}
+	created := &CustomGroup{}
+	if err := json.Unmarshal(b, created); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return created, nil
+}
+
+// UpdateCustomGroup updates the custom group.
+func (c *Client) UpdateCustomGroup(g *CustomGroup) (*CustomGroup, error) {
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+	if g.ID == "" {
+		return nil, fmt.Errorf("empty custom group id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.requestWithPayload("PUT", "resources/groups", params, g)
+	if err != nil {
+		return nil, err
+	}
+	updated := &CustomGroup{}
+	if err := json.Unmarshal(b, updated); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return updated, nil
+}
+
+// DeleteCustomGroup deletes the custom group with the provided identifier.
+func (c *Client) DeleteCustomGroup(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty custom group id")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	if _, err := c.request("DELETE", "resources/groups/"+id, params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetCustomGroupRules replaces the dynamic membership rules of the custom
+// group with the provided identifier.
+func (c *Client) SetCustomGroupRules(id string, rules []*GroupMembershipRule) (*CustomGroup, error) {
+	g, err := c.GetCustomGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	if g.Membership == nil {
+		g.Membership = &GroupMembership{}
+	}
+	g.Membership.Rules = rules
+	g.AutoResolveMembership = len(rules) > 0
+	return c.UpdateCustomGroup(g)
+}
+
+// GetCustomGroupMembers returns the resources that are members of the
+// custom group with the provided identifier.
+func (c *Client) GetCustomGroupMembers(id string) ([]*Resource, error) {
+	if id == "" {
+		return []*Resource{}, fmt.Errorf("empty custom group id")
+	}
+	return c.getResources("resources/groups/"+id+"/members", nil)
+}
+
+// ToJSONString serializes CustomGroup to a string.
+func (g *CustomGroup) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(g)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
+
+func (g *CustomGroup) validate() error {
+	if g == nil {
+		return fmt.Errorf("nil custom group")
+	}
+	if g.Key == nil || g.Key.Name == "" {
+		return fmt.Errorf("custom group name is empty")
+	}
+	if g.Key.AdapterKindKey == "" || g.Key.ResourceKindKey == "" {
+		return fmt.Errorf("custom group %s has no adapter kind or group type", g.Key.Name)
+	}
+	return nil
+}
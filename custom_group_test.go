@@ -0,0 +1,217 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	. "github.com/greenpau/go-vrop/internal/server"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testCustomGroupID = "6a1f4b3c-2d5e-4f60-8a71-9b8c7d6e5f40"
+
+func newCustomGroupMockClient(t *testing.T) (*Client, *MockTestServer) {
+	return newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/resources/groups": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "custom_groups.json"},
+			&MockTestEndpoint{Method: "POST", FileName: "custom_group.json"},
+			&MockTestEndpoint{Method: "PUT", FileName: "custom_group.json"},
+		},
+		"/suite-api/api/resources/groups/" + testCustomGroupID: []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "custom_group.json"},
+			&MockTestEndpoint{Method: "DELETE"},
+		},
+		"/suite-api/api/resources/groups/" + testCustomGroupID + "/members": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "custom_group_members.json"},
+		},
+	})
+}
+
+// lastRequest returns the last request received by the mock server,
+// and unpacks its payload, when provided.
+func lastRequest(t *testing.T, server *MockTestServer, payload interface{}) *MockTestRequest {
+	requests := server.Requests()
+	req := requests[len(requests)-1]
+	if payload != nil {
+		if err := json.Unmarshal(req.Body, payload); err != nil {
+			t.Fatalf("failed unpacking request payload: %s", err)
+		}
+	}
+	return req
+}
+
+func TestCustomGroupLifecycle(t *testing.T) {
+	cli, server := newCustomGroupMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	groups, err := cli.GetCustomGroups()
+	if err != nil {
+		t.Fatalf("failed getting custom groups: %s", err)
+	}
+	if len(groups) != 1 || groups[0].ID != testCustomGroupID || groups[0].Key.Name != "web-servers" {
+		t.Fatalf("unexpected custom groups: %+v", groups)
+	}
+
+	for _, g := range []*CustomGroup{nil, &CustomGroup{}, NewCustomGroup("web-servers", "")} {
+		if _, err := cli.CreateCustomGroup(g); err == nil {
+			t.Fatalf("expected error for invalid custom group %+v, got none", g)
+		}
+	}
+
+	g := NewCustomGroup("web-servers", "Environment")
+	g.ID = "ignored"
+	g.Membership.IncludedResources = []string{"3b9d2c1a-7e4f-4a8b-9c6d-5e4f3a2b1c0d"}
+	created, err := cli.CreateCustomGroup(g)
+	if err != nil {
+		t.Fatalf("failed creating custom group: %s", err)
+	}
+	if created.ID != testCustomGroupID {
+		t.Fatalf("unexpected created custom group id: %s", created.ID)
+	}
+	if g.ID != "ignored" {
+		t.Fatalf("custom group was modified on create: %+v", g)
+	}
+	payload := map[string]interface{}{}
+	req := lastRequest(t, server, &payload)
+	if req.Method != "POST" || !strings.HasPrefix(req.RequestURI, "/suite-api/api/resources/groups?") {
+		t.Fatalf("unexpected request: %s %s", req.Method, req.RequestURI)
+	}
+	want := map[string]interface{}{
+		"resourceKey": map[string]interface{}{
+			"name":            "web-servers",
+			"adapterKindKey":  "Container",
+			"resourceKindKey": "Environment",
+		},
+		"autoResolveMembership": false,
+		"membershipDefinition": map[string]interface{}{
+			"includedResources": []interface{}{"3b9d2c1a-7e4f-4a8b-9c6d-5e4f3a2b1c0d"},
+		},
+	}
+	if !reflect.DeepEqual(payload, want) {
+		t.Fatalf("unexpected create payload:\ngot:  %v\nwant: %v", payload, want)
+	}
+
+	created.Membership.ExcludedResources = []string{"8e7d6c5b-4a39-4281-b7c6-d5e4f3a2b1c0"}
+	if _, err := cli.UpdateCustomGroup(created); err != nil {
+		t.Fatalf("failed updating custom group: %s", err)
+	}
+	updated := &CustomGroup{}
+	req = lastRequest(t, server, updated)
+	if req.Method != "PUT" {
+		t.Fatalf("unexpected request method: %s", req.Method)
+	}
+	if updated.ID != testCustomGroupID || !reflect.DeepEqual(updated.Membership.ExcludedResources, created.Membership.ExcludedResources) {
+		t.Fatalf("unexpected update payload: %s", req.Body)
+	}
+	if _, err := cli.UpdateCustomGroup(NewCustomGroup("web-servers", "Environment")); err == nil {
+		t.Fatalf("expected error for custom group without id, got none")
+	}
+
+	if err := cli.DeleteCustomGroup(testCustomGroupID); err != nil {
+		t.Fatalf("failed deleting custom group: %s", err)
+	}
+	req = lastRequest(t, server, nil)
+	if req.Method != "DELETE" || !strings.HasPrefix(req.RequestURI, "/suite-api/api/resources/groups/"+testCustomGroupID) {
+		t.Fatalf("unexpected request: %s %s", req.Method, req.RequestURI)
+	}
+	if err := cli.DeleteCustomGroup(""); err == nil {
+		t.Fatalf("expected error for empty custom group id, got none")
+	}
+}
+
+func TestSetCustomGroupRules(t *testing.T) {
+	cli, server := newCustomGroupMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	rules := []*GroupMembershipRule{
+		&GroupMembershipRule{
+			ResourceKind: &GroupResourceKind{ResourceKind: "VirtualMachine", AdapterKind: "VMWARE"},
+			PropertyConditionRules: []*GroupConditionRule{
+				&GroupConditionRule{Key: "summary|guest|fullName", StringValue: "Linux", CompareOperator: "CONTAINS"},
+			},
+			StatConditionRules: []*GroupConditionRule{
+				&GroupConditionRule{Key: "cpu|usage_average", DoubleValue: 80, CompareOperator: "GT"},
+			},
+			RelationshipConditionRules: []*GroupConditionRule{
+				&GroupConditionRule{Name: "cluster-01", Relation: "DESCENDANT", CompareOperator: "EQ"},
+			},
+			ResourceTagConditionRules: []*GroupConditionRule{
+				&GroupConditionRule{Category: "Environment", StringValue: "prod", CompareOperator: "EQ"},
+			},
+		},
+	}
+	if _, err := cli.SetCustomGroupRules(testCustomGroupID, rules); err != nil {
+		t.Fatalf("failed setting custom group rules: %s", err)
+	}
+
+	payload := map[string]interface{}{}
+	req := lastRequest(t, server, &payload)
+	if req.Method != "PUT" {
+		t.Fatalf("unexpected request method: %s", req.Method)
+	}
+	if payload["id"] != testCustomGroupID || payload["autoResolveMembership"] != true {
+		t.Fatalf("unexpected rules payload: %s", req.Body)
+	}
+	membership := payload["membershipDefinition"].(map[string]interface{})
+	if !reflect.DeepEqual(membership["includedResources"], []interface{}{"3b9d2c1a-7e4f-4a8b-9c6d-5e4f3a2b1c0d"}) {
+		t.Fatalf("included resources were not preserved: %s", req.Body)
+	}
+	got, err := json.Marshal(membership["rules"])
+	if err != nil {
+		t.Fatalf("failed packing rules: %s", err)
+	}
+	want := `[{"propertyConditionRules":[{"compareOperator":"CONTAINS","key":"summary|guest|fullName","stringValue":"Linux"}],` +
+		`"relationshipConditionRules":[{"compareOperator":"EQ","name":"cluster-01","relation":"DESCENDANT"}],` +
+		`"resourceKindKey":{"adapterKind":"VMWARE","resourceKind":"VirtualMachine"},` +
+		`"resourceTagConditionRules":[{"category":"Environment","compareOperator":"EQ","stringValue":"prod"}],` +
+		`"statConditionRules":[{"compareOperator":"GT","doubleValue":80,"key":"cpu|usage_average"}]}]`
+	if string(got) != want {
+		t.Fatalf("unexpected rules payload:\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestGetCustomGroupMembers(t *testing.T) {
+	cli, server := newCustomGroupMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	if _, err := cli.GetCustomGroupMembers(""); err == nil {
+		t.Fatalf("expected error for empty custom group id, got none")
+	}
+
+	members, err := cli.GetCustomGroupMembers(testCustomGroupID)
+	if err != nil {
+		t.Fatalf("failed getting custom group members: %s", err)
+	}
+	var names []string
+	for _, r := range members {
+		names = append(names, r.Key.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"web01", "web02"}) {
+		t.Fatalf("unexpected custom group members: %v", names)
+	}
+	if members[0].ID != "3b9d2c1a-7e4f-4a8b-9c6d-5e4f3a2b1c0d" {
+		t.Fatalf("unexpected custom group member id: %s", members[0].ID)
+	}
+
+	req := lastRequest(t, server, nil)
+	if req.RequestURI != "/suite-api/api/resources/groups/"+testCustomGroupID+"/members?page=0&pageSize=100" {
+		t.Fatalf("unexpected request uri: %s", req.RequestURI)
+	}
+}
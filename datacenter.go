@@ -0,0 +1,56 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Datacenter is a vSphere datacenter.
+type Datacenter struct {
+	InventoryItem
+	// The name of the vCenter Server managing the datacenter.
+	ParentVCenter string `json:"parent_vcenter,omitempty"`
+}
+
+// GetDatacenters returns a list of Datacenter instances, selected by the
+// options of GetResources, e.g. name or tags.
+func (c *Client) GetDatacenters(opts map[string]interface{}) ([]*Datacenter, error) {
+	datacenters := []*Datacenter{}
+	err := c.getInventory("VMWARE", "Datacenter", opts, func() inventoryResource {
+		m := &Datacenter{}
+		datacenters = append(datacenters, m)
+		return m
+	})
+	return datacenters, err
+}
+
+// parse populates the typed fields of Datacenter from its properties.
+func (m *Datacenter) parse(r *Resource) []error {
+	if v := m.Properties["summary|parentVcenter"]; v != "" {
+		m.ParentVCenter = v
+	}
+	return nil
+}
+
+// ToJSONString serializes Datacenter to a string.
+func (m *Datacenter) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Datastore is a vSphere datastore.
+type Datastore struct {
+	InventoryItem
+	// The type of the datastore, e.g. VMFS, NFS, vsan.
+	Type string `json:"type,omitempty"`
+	// The URL of the datastore, e.g. ds:///vmfs/volumes/5e5a0c1d-.../
+	URL string `json:"url,omitempty"`
+	// Whether the datastore is accessible, and whether it is shared
+	// by multiple hosts.
+	Accessible         bool `json:"accessible,omitempty"`
+	MultipleHostAccess bool `json:"multiple_host_access,omitempty"`
+	// The names of the parents of the datastore.
+	ParentDatacenter string `json:"parent_datacenter,omitempty"`
+	ParentVCenter    string `json:"parent_vcenter,omitempty"`
+}
+
+// GetDatastores returns a list of Datastore instances, selected by the
+// options of GetResources, e.g. name or tags.
+func (c *Client) GetDatastores(opts map[string]interface{}) ([]*Datastore, error) {
+	stores := []*Datastore{}
+	err := c.getInventory("VMWARE", "Datastore", opts, func() inventoryResource {
+		m := &Datastore{}
+		stores = append(stores, m)
+		return m
+	})
+	return stores, err
+}
+
+// parse populates the typed fields of Datastore from its properties.
+func (m *Datastore) parse(r *Resource) []error {
+	p := &propertyParser{kind: "Datastore"}
+	for k, v := range m.Properties {
+		if v == "" {
+			continue
+		}
+		switch k {
+		case "summary|type":
+			m.Type = v
+		case "summary|url":
+			m.URL = v
+		case "summary|accessible":
+			m.Accessible = p.parseBool(k, v)
+		case "summary|multipleHostAccess":
+			m.MultipleHostAccess = p.parseBool(k, v)
+		case "summary|parentDatacenter":
+			m.ParentDatacenter = v
+		case "summary|parentVcenter":
+			m.ParentVCenter = v
+		}
+	}
+	return p.errors
+}
+
+// ToJSONString serializes Datastore to a string.
+func (m *Datastore) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
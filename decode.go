@@ -0,0 +1,232 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+)
+
+// DecodeError is an error unpacking an API response. The path locates
+// the offending value in the response, e.g. resourceList[3].resourceKey.name.
+type DecodeError struct {
+	Path    string
+	Message string
+}
+
+// Error returns the path and the description of DecodeError.
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+func decodeErrorf(path, format string, args ...interface{}) error {
+	return &DecodeError{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// decoder holds the options of unpacking API responses. In strict mode,
+// the default, the unpacking fails when a response contains a key not
+// supported by this package. In lenient mode, the values of such keys are
+// preserved in the Extra field of the unpacked object, and a warning is
+// recorded.
+type decoder struct {
+	lenient  bool
+	warnings []string
+}
+
+// newDecoder returns a decoder with the options of Client.
+func (c *Client) newDecoder() *decoder {
+	return &decoder{
+		lenient: c.lenientDecoding,
+	}
+}
+
+// logWarnings sends the warnings recorded by the decoder to the
+// configured logger.
+func (c *Client) logWarnings(warnings []string) {
+	for _, warning := range warnings {
+		c.log.Warn("unsupported data in response", zap.String("warning", warning))
+	}
+}
+
+// unknownKey handles the key of the object at the path not supported by
+// this package. In strict mode, it returns an error. In lenient mode, it
+// preserves the value of the key in extra and records a warning.
+func (d *decoder) unknownKey(path, k string, v interface{}, extra *map[string]json.RawMessage) error {
+	if d == nil || !d.lenient {
+		return decodeErrorf(path, "unsupported key: %s", k)
+	}
+	if extra != nil {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return decodeErrorf(joinPath(path, k), "failed to preserve value: %s", err)
+		}
+		if *extra == nil {
+			*extra = make(map[string]json.RawMessage)
+		}
+		(*extra)[k] = raw
+	}
+	d.warnings = append(d.warnings, fmt.Sprintf("unsupported key: %s", joinPath(path, k)))
+	return nil
+}
+
+// enum returns the string value at the path, validated with the provided
+// function. An unknown value is returned as-is and a warning is recorded,
+// in strict mode too, because newer versions of the server add values to
+// the enumerations, e.g. badge types, and they must not fail the unpacking.
+func (d *decoder) enum(v interface{}, path string, valid func(string) bool) (string, error) {
+	s, err := asString(v, path)
+	if err != nil {
+		return "", err
+	}
+	if s == "" || valid(s) || d == nil {
+		return s, nil
+	}
+	d.warnings = append(d.warnings, fmt.Sprintf("unsupported value: %s: %s", path, s))
+	return s, nil
+}
+
+// Warnings returns the warnings recorded by the decoder.
+func (d *decoder) Warnings() []string {
+	if d == nil {
+		return nil
+	}
+	return d.warnings
+}
+
+// withExtra adds the values of the keys not supported by this package, if
+// any, to the wire representation of an object.
+func withExtra(m map[string]interface{}, extra map[string]json.RawMessage) map[string]interface{} {
+	for k, v := range extra {
+		if _, exists := m[k]; !exists {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// keySet is the set of the keys of an object found in a response. It is
+// recorded when unpacking, so that the object is packed with the same keys.
+type keySet map[string]bool
+
+// newKeySet returns the set of the keys of the object.
+func newKeySet(m map[string]interface{}) keySet {
+	keys := make(keySet)
+	for k := range m {
+		keys[k] = true
+	}
+	return keys
+}
+
+// wireObject is the wire representation of an object being packed. The
+// key is packed when it was found in the response the object was unpacked
+// from, or when its value is set.
+type wireObject struct {
+	m    map[string]interface{}
+	keys keySet
+}
+
+func newWireObject(keys keySet) *wireObject {
+	return &wireObject{
+		m:    make(map[string]interface{}),
+		keys: keys,
+	}
+}
+
+// set adds the key to the object when it was found in the response, or
+// when isSet is true.
+func (w *wireObject) set(k string, v interface{}, isSet bool) {
+	if isSet || w.keys[k] {
+		w.m[k] = v
+	}
+}
+
+// joinPath returns the path of the key of the object at the path.
+func joinPath(path, k string) string {
+	if path == "" {
+		return k
+	}
+	return path + "." + k
+}
+
+// indexPath returns the path of the element of the array at the path.
+func indexPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+// jsonTypeName returns the JSON type of the value decoded by encoding/json.
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func asObject(v interface{}, path string) (map[string]interface{}, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, decodeErrorf(path, "expected object, found %s", jsonTypeName(v))
+	}
+	return m, nil
+}
+
+func asArray(v interface{}, path string) ([]interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok {
+		return nil, decodeErrorf(path, "expected array, found %s", jsonTypeName(v))
+	}
+	return a, nil
+}
+
+func asString(v interface{}, path string) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", decodeErrorf(path, "expected string, found %s", jsonTypeName(v))
+	}
+	return s, nil
+}
+
+func asNumber(v interface{}, path string) (float64, error) {
+	n, ok := v.(float64)
+	if !ok {
+		return 0, decodeErrorf(path, "expected number, found %s", jsonTypeName(v))
+	}
+	return n, nil
+}
+
+func asBool(v interface{}, path string) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, decodeErrorf(path, "expected boolean, found %s", jsonTypeName(v))
+	}
+	return b, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package vrop
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+func FuzzResourcesResponse(f *testing.F) {
+	b, err := ioutil.ReadFile("testdata/responses/virtual_machines.json")
+	if err != nil {
+		f.Fatalf("failed reading test data: %s", err)
+	}
+	f.Add(b)
+	f.Add(b[:len(b)/2])
+	f.Add([]byte(`{"pageInfo":null,"links":null,"resourceList":null}`))
+	f.Add([]byte(`{"pageInfo":{},"links":[null],"resourceList":[{"resourceKey":null}]}`))
+	f.Add([]byte(`{"pageInfo":{"page":"0"},"links":[],"resourceList":[{"badges":[{"score":"1"}]}]}`))
+	f.Add([]byte(`{"pageInfo":{},"links":[],"resourceList":[{"resourceKey":{"resourceIdentifiers":[{"identifierType":[],"value":1}]}}]}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// The unpacking must return an error rather than panic.
+		json.Unmarshal(data, &ResourcesResponse{})
+		json.Unmarshal(data, &ResourcesResponse{Lenient: true})
+	})
+}
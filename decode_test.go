@@ -0,0 +1,227 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestLenientDecoding(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/responses/virtual_machines.json")
+	if err != nil {
+		t.Fatalf("failed reading test data: %s", err)
+	}
+	strict := &ResourcesResponse{}
+	if err := json.Unmarshal(b, strict); err != nil {
+		t.Fatalf("failed unpacking test data in strict mode: %s", err)
+	}
+
+	// Add the keys a newer version of the server could return.
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("failed unmarshalling test data: %s", err)
+	}
+	m["facets"] = []interface{}{}
+	resource := m["resourceList"].([]interface{})[0].(map[string]interface{})
+	resource["resourceHealthTrend"] = "UP"
+	badge := resource["badges"].([]interface{})[0].(map[string]interface{})
+	badge["trend"] = 1.5
+	b, err = json.Marshal(m)
+	if err != nil {
+		t.Fatalf("failed marshalling test data: %s", err)
+	}
+
+	strict = &ResourcesResponse{}
+	if err := json.Unmarshal(b, strict); err == nil {
+		t.Fatalf("expected failure in strict mode, but succeeded")
+	}
+
+	lenient := &ResourcesResponse{Lenient: true}
+	if err := json.Unmarshal(b, lenient); err != nil {
+		t.Fatalf("failed unpacking test data in lenient mode: %s", err)
+	}
+	if len(lenient.Warnings) != 3 {
+		t.Fatalf("expected 3 warnings, got %d: %v", len(lenient.Warnings), lenient.Warnings)
+	}
+	if string(lenient.Extra["facets"]) != "[]" {
+		t.Fatalf("unexpected response extra: %v", lenient.Extra)
+	}
+	r := lenient.Resources[0]
+	if string(r.Extra["resourceHealthTrend"]) != `"UP"` {
+		t.Fatalf("unexpected resource extra: %v", r.Extra)
+	}
+	if string(r.Badges[0].Extra["trend"]) != "1.5" {
+		t.Fatalf("unexpected badge extra: %v", r.Badges[0].Extra)
+	}
+}
+
+func TestDecodeErrorPath(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/responses/virtual_machines.json")
+	if err != nil {
+		t.Fatalf("failed reading test data: %s", err)
+	}
+	testcases := []struct {
+		name   string
+		modify func(resource map[string]interface{})
+		path   string
+	}{
+		{
+			name: "null resource name",
+			modify: func(resource map[string]interface{}) {
+				resource["resourceKey"].(map[string]interface{})["name"] = nil
+			},
+			path: "resourceList[0].resourceKey.name",
+		},
+		{
+			name: "numeric badge color",
+			modify: func(resource map[string]interface{}) {
+				resource["badges"].([]interface{})[1].(map[string]interface{})["color"] = 1.0
+			},
+			path: "resourceList[0].badges[1].color",
+		},
+		{
+			name: "badges not array",
+			modify: func(resource map[string]interface{}) {
+				resource["badges"] = "GREEN"
+			},
+			path: "resourceList[0].badges",
+		},
+		{
+			name: "null resource",
+			modify: func(resource map[string]interface{}) {
+				for k := range resource {
+					delete(resource, k)
+				}
+				resource["identifier"] = false
+			},
+			path: "resourceList[0].identifier",
+		},
+	}
+	for _, tc := range testcases {
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatalf("failed unmarshalling test data: %s", err)
+		}
+		tc.modify(m["resourceList"].([]interface{})[0].(map[string]interface{}))
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatalf("failed marshalling test data: %s", err)
+		}
+		resp := &ResourcesResponse{}
+		err = json.Unmarshal(data, resp)
+		if err == nil {
+			t.Fatalf("%s: expected failure, but succeeded", tc.name)
+		}
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Fatalf("%s: expected DecodeError, got: %s", tc.name, err)
+		}
+		if decodeErr.Path != tc.path {
+			t.Fatalf("%s: expected error at %s, got: %s", tc.name, tc.path, err)
+		}
+	}
+}
+
+func TestUnpackResourceIdentifier(t *testing.T) {
+	testcases := []struct {
+		data    string
+		value   interface{}
+		invalid bool
+	}{
+		{data: `{"identifierType":{"name":"ID","dataType":"STRING","isPartOfUniqueness":true},"value":"a"}`, value: "a"},
+		{data: `{"identifierType":{"name":"VNI","dataType":"INTEGER","isPartOfUniqueness":true},"value":"5001"}`, value: int64(5001)},
+		{data: `{"identifierType":{"name":"Ratio","dataType":"DOUBLE","isPartOfUniqueness":false},"value":"0.5"}`, value: 0.5},
+		{data: `{"identifierType":{"name":"Enabled","dataType":"BOOLEAN","isPartOfUniqueness":false},"value":true}`, value: true},
+		{data: `{"identifierType":{"name":"VNI","dataType":"INTEGER","isPartOfUniqueness":true},"value":"x"}`, invalid: true},
+		{data: `{"identifierType":{"name":"VNI","dataType":"DATE","isPartOfUniqueness":true},"value":"1"}`, invalid: true},
+	}
+	for _, tc := range testcases {
+		var m interface{}
+		if err := json.Unmarshal([]byte(tc.data), &m); err != nil {
+			t.Fatalf("failed unmarshalling test data: %s", err)
+		}
+		id, err := unpackResourceIdentifier(m, "", nil)
+		if tc.invalid {
+			if err == nil {
+				t.Fatalf("%s: expected failure, but succeeded", tc.data)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected failure: %s", tc.data, err)
+		}
+		v, err := id.TypedValue()
+		if err != nil {
+			t.Fatalf("%s: unexpected failure: %s", tc.data, err)
+		}
+		if v != tc.value {
+			t.Fatalf("%s: expected value %v (%T), got %v (%T)", tc.data, tc.value, tc.value, v, v)
+		}
+		// The identifier is sent back with the same type.
+		payload := id.payload()
+		if !reflect.DeepEqual(payload["identifierType"], m.(map[string]interface{})["identifierType"]) {
+			t.Fatalf("%s: unexpected payload: %v", tc.data, payload)
+		}
+	}
+}
+
+func TestResourceExtension(t *testing.T) {
+	data := `{"identifier":"1","resourceKey":{"name":"vm1","adapterKindKey":"VMWARE","resourceKindKey":"VirtualMachine","resourceIdentifiers":[],"extension":{"pack":"acme"}},"extension":{"owner":"ops","tier":2}}`
+	var m interface{}
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		t.Fatalf("failed unmarshalling test data: %s", err)
+	}
+	r, err := unpackResource(m, "", nil)
+	if err != nil {
+		t.Fatalf("failed unpacking resource: %s", err)
+	}
+	ext := struct {
+		Owner string `json:"owner"`
+		Tier  int    `json:"tier"`
+	}{}
+	if err := r.DecodeExtension(&ext); err != nil {
+		t.Fatalf("failed decoding extension: %s", err)
+	}
+	if ext.Owner != "ops" || ext.Tier != 2 {
+		t.Fatalf("unexpected extension: %+v", ext)
+	}
+	keyExt := map[string]string{}
+	if err := r.Key.DecodeExtension(&keyExt); err != nil {
+		t.Fatalf("failed decoding resource key extension: %s", err)
+	}
+	if keyExt["pack"] != "acme" {
+		t.Fatalf("unexpected resource key extension: %v", keyExt)
+	}
+
+	// The extensions are sent back unchanged.
+	payload, err := json.Marshal(r.payload())
+	if err != nil {
+		t.Fatalf("failed marshalling payload: %s", err)
+	}
+	var sent map[string]interface{}
+	if err := json.Unmarshal(payload, &sent); err != nil {
+		t.Fatalf("failed unmarshalling payload: %s", err)
+	}
+	if !reflect.DeepEqual(sent["extension"], m.(map[string]interface{})["extension"]) {
+		t.Fatalf("unexpected payload extension: %s", payload)
+	}
+	if !reflect.DeepEqual(sent["resourceKey"].(map[string]interface{})["extension"], map[string]interface{}{"pack": "acme"}) {
+		t.Fatalf("unexpected payload resource key extension: %s", payload)
+	}
+}
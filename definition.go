@@ -0,0 +1,207 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// definition is a symptom or alert definition, applied by applyDefinition.
+type definition interface {
+	// identity returns the identifier, the name, and the kinds of the
+	// definition, i.e. what the existing definition is looked up by.
+	identity() definitionIdentity
+	// desired returns a copy of the definition with the identifier and the
+	// fields not supported by this package taken from the current one, so
+	// that an update does not drop them from the server.
+	desired(current definition) (definition, error)
+}
+
+type definitionIdentity struct {
+	ID              string
+	Name            string
+	AdapterKindKey  string
+	ResourceKindKey string
+}
+
+// applyDefinition creates or updates the definition so that the server has
+// the same definition. The existing definition is looked up by identifier,
+// then by name and kinds. The definition is not updated when it already
+// matches. It returns the applied definition and whether it was changed.
+func applyDefinition(d definition, existing []definition, create, update func(definition) (definition, error)) (definition, bool, error) {
+	current := findDefinition(d, existing)
+	if current == nil {
+		created, err := create(d)
+		if err != nil {
+			return nil, false, err
+		}
+		return created, true, nil
+	}
+	desired, err := d.desired(current)
+	if err != nil {
+		return nil, false, err
+	}
+	if jsonEqual(desired, current) {
+		return current, false, nil
+	}
+	updated, err := update(desired)
+	if err != nil {
+		return nil, false, err
+	}
+	return updated, true, nil
+}
+
+func findDefinition(d definition, existing []definition) definition {
+	id := d.identity()
+	if id.ID != "" {
+		for _, item := range existing {
+			if item.identity().ID == id.ID {
+				return item
+			}
+		}
+	}
+	for _, item := range existing {
+		other := item.identity()
+		if other.Name == id.Name && other.AdapterKindKey == id.AdapterKindKey && other.ResourceKindKey == id.ResourceKindKey {
+			return item
+		}
+	}
+	return nil
+}
+
+// definitionFilter converts the options of definition listing calls
+// to query parameters.
+func definitionFilter(opts map[string]interface{}) (map[string]string, error) {
+	params := make(map[string]string)
+	for k, v := range opts {
+		var err error
+		switch k {
+		case "adapter_kind":
+			params["adapterKind"], err = stringOption(k, v)
+		case "resource_kind":
+			params["resourceKind"], err = stringOption(k, v)
+		default:
+			err = unsupportedOption(k)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// unpackWithExtra unpacks the JSON object into v, a pointer to a struct,
+// and returns the values of the keys not supported by the struct.
+func unpackWithExtra(b []byte, v interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	var extra map[string]json.RawMessage
+	for k, raw := range m {
+		if known[k] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[k] = raw
+	}
+	return extra, nil
+}
+
+// packWithExtra packs v, a struct, with the values of the keys not
+// supported by the struct added back.
+func packWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for k, raw := range extra {
+		if _, exists := m[k]; !exists {
+			m[k] = raw
+		}
+	}
+	return json.Marshal(m)
+}
+
+// jsonFieldNames returns the JSON keys of the fields of the struct type.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// inheritExtra returns the values of the keys not supported by this
+// package, with the ones missing in dst taken from src.
+func inheritExtra(dst, src map[string]json.RawMessage) map[string]json.RawMessage {
+	for k, raw := range src {
+		if dst == nil {
+			dst = make(map[string]json.RawMessage)
+		}
+		if _, exists := dst[k]; !exists {
+			dst[k] = raw
+		}
+	}
+	return dst
+}
+
+// jsonCopy copies src to dst, both pointers to the same type, through
+// their JSON representation, i.e. a deep copy.
+func jsonCopy(src, dst interface{}) error {
+	b, err := json.Marshal(src)
+	if err != nil {
+		return fmt.Errorf("failed copying definition: %s", err)
+	}
+	if err := json.Unmarshal(b, dst); err != nil {
+		return fmt.Errorf("failed copying definition: %s", err)
+	}
+	return nil
+}
+
+// jsonEqual returns true when the objects have the same JSON representation.
+func jsonEqual(a, b interface{}) bool {
+	ab, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ab, bb)
+}
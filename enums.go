@@ -0,0 +1,151 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+// ResourceState is the state of a resource, as reported by
+// an adapter instance.
+type ResourceState string
+
+// The states of resources.
+const (
+	// StateStarted is the state of the resource being collected.
+	StateStarted ResourceState = "STARTED"
+	// StateStopped is the state of the resource not being collected.
+	StateStopped ResourceState = "STOPPED"
+	// StateMaintained is the state of the resource in scheduled maintenance.
+	StateMaintained ResourceState = "MAINTAINED"
+	// StateMaintainedManual is the state of the resource in maintenance
+	// until unmarked.
+	StateMaintainedManual ResourceState = "MAINTAINED_MANUAL"
+	// StateNotExisting is the state of a non-existing resource.
+	StateNotExisting ResourceState = "NOT_EXISTING"
+	// StateNone is the state of the resource not associated with
+	// an adapter instance.
+	StateNone ResourceState = "NONE"
+	// StateUnknown serves as a means to ensure older clients can talk
+	// to newer servers.
+	StateUnknown ResourceState = "UNKNOWN"
+)
+
+// ResourceStatus is the data collection status of a resource, as reported
+// by an adapter instance.
+type ResourceStatus string
+
+// The data collection statuses of resources.
+const (
+	StatusNone               ResourceStatus = "NONE"
+	StatusError              ResourceStatus = "ERROR"
+	StatusUnknown            ResourceStatus = "UNKNOWN"
+	StatusDown               ResourceStatus = "DOWN"
+	StatusDataReceiving      ResourceStatus = "DATA_RECEIVING"
+	StatusOldDataReceiving   ResourceStatus = "OLD_DATA_RECEIVING"
+	StatusNoDataReceiving    ResourceStatus = "NO_DATA_RECEIVING"
+	StatusNoParentMonitoring ResourceStatus = "NO_PARENT_MONITORING"
+	StatusCollectorDown      ResourceStatus = "COLLECTOR_DOWN"
+)
+
+// BadgeType is the type of a badge.
+type BadgeType string
+
+// The types of badges.
+const (
+	BadgeHealth              BadgeType = "HEALTH"
+	BadgeRisk                BadgeType = "RISK"
+	BadgeEfficiency          BadgeType = "EFFICIENCY"
+	BadgeAnomaly             BadgeType = "ANOMALY"
+	BadgeFault               BadgeType = "FAULT"
+	BadgeWorkload            BadgeType = "WORKLOAD"
+	BadgeDensity             BadgeType = "DENSITY"
+	BadgeStress              BadgeType = "STRESS"
+	BadgeWaste               BadgeType = "WASTE"
+	BadgeCapacityRemaining   BadgeType = "CAPACITY_REMAINING"
+	BadgeTimeRemaining       BadgeType = "TIME_REMAINING"
+	BadgeReclaimableCapacity BadgeType = "RECLAIMABLE_CAPACITY"
+	BadgeCompliance          BadgeType = "COMPLIANCE"
+)
+
+// Color is the color of the health of a resource, or of a badge.
+type Color string
+
+// The colors of health and badges.
+const (
+	ColorGreen  Color = "GREEN"
+	ColorYellow Color = "YELLOW"
+	ColorOrange Color = "ORANGE"
+	ColorRed    Color = "RED"
+	// ColorGrey means the color is unknown, e.g. no data.
+	ColorGrey Color = "GREY"
+)
+
+// String returns the value of ResourceState.
+func (s ResourceState) String() string {
+	return string(s)
+}
+
+// Valid returns true when ResourceState is one of the known states.
+func (s ResourceState) Valid() bool {
+	switch s {
+	case StateStarted, StateStopped, StateMaintained, StateMaintainedManual,
+		StateNotExisting, StateNone, StateUnknown:
+		return true
+	}
+	return false
+}
+
+// String returns the value of ResourceStatus.
+func (s ResourceStatus) String() string {
+	return string(s)
+}
+
+// Valid returns true when ResourceStatus is one of the known statuses.
+func (s ResourceStatus) Valid() bool {
+	switch s {
+	case StatusNone, StatusError, StatusUnknown, StatusDown, StatusDataReceiving,
+		StatusOldDataReceiving, StatusNoDataReceiving, StatusNoParentMonitoring,
+		StatusCollectorDown:
+		return true
+	}
+	return false
+}
+
+// String returns the value of BadgeType.
+func (t BadgeType) String() string {
+	return string(t)
+}
+
+// Valid returns true when BadgeType is one of the known types.
+func (t BadgeType) Valid() bool {
+	switch t {
+	case BadgeHealth, BadgeRisk, BadgeEfficiency, BadgeAnomaly, BadgeFault,
+		BadgeWorkload, BadgeDensity, BadgeStress, BadgeWaste, BadgeCapacityRemaining,
+		BadgeTimeRemaining, BadgeReclaimableCapacity, BadgeCompliance:
+		return true
+	}
+	return false
+}
+
+// String returns the value of Color.
+func (c Color) String() string {
+	return string(c)
+}
+
+// Valid returns true when Color is one of the known colors.
+func (c Color) Valid() bool {
+	switch c {
+	case ColorGreen, ColorYellow, ColorOrange, ColorRed, ColorGrey:
+		return true
+	}
+	return false
+}
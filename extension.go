@@ -0,0 +1,87 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DecodeExtension decodes the extension values added to Resource by
+// third-party, e.g. a management pack, into the provided value.
+func (r *Resource) DecodeExtension(v interface{}) error {
+	return decodeExtension(r.Extension, v)
+}
+
+// SetExtension sets the extension values of Resource to the JSON
+// representation of the provided value.
+func (r *Resource) SetExtension(v interface{}) error {
+	raw, err := encodeExtension(v)
+	if err != nil {
+		return err
+	}
+	r.Extension = raw
+	return nil
+}
+
+// DecodeExtension decodes the extension values added to ResourceKey by
+// third-party, e.g. a management pack, into the provided value.
+func (p *ResourceKey) DecodeExtension(v interface{}) error {
+	return decodeExtension(p.Extension, v)
+}
+
+// SetExtension sets the extension values of ResourceKey to the JSON
+// representation of the provided value.
+func (p *ResourceKey) SetExtension(v interface{}) error {
+	raw, err := encodeExtension(v)
+	if err != nil {
+		return err
+	}
+	p.Extension = raw
+	return nil
+}
+
+// unpackExtension preserves the extension values at the path as raw JSON.
+func unpackExtension(v interface{}, path string) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, decodeErrorf(path, "failed to preserve extension: %s", err)
+	}
+	return raw, nil
+}
+
+func decodeExtension(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 {
+		return fmt.Errorf("extension not found")
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("failed to decode extension: %s", err)
+	}
+	return nil
+}
+
+func encodeExtension(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode extension: %s", err)
+	}
+	return raw, nil
+}
@@ -1,7 +1,7 @@
 package vrop
 
 import (
-	"fmt"
+	"encoding/json"
 )
 
 // GeoLocation is geographical location.
@@ -10,29 +10,44 @@ type GeoLocation struct {
 	Latitude float64 `json:"latitude,omitempty"`
 	// Longitude of the location.
 	Longitude float64 `json:"longitude,omitempty"`
+	// Extra holds the values of the keys not supported by this package,
+	// when decoded in lenient mode.
+	Extra map[string]json.RawMessage `json:"-"`
+	// keys are the keys found in the response the GeoLocation was unpacked from.
+	keys keySet
 }
 
-func unpackGeoLocation(m interface{}) (*GeoLocation, error) {
-	var pim map[string]interface{}
-
-	switch m.(type) {
-	case map[string]interface{}:
-		pim = m.(map[string]interface{})
-	default:
-		return nil, fmt.Errorf("map not found")
+func unpackGeoLocation(m interface{}, path string, d *decoder) (*GeoLocation, error) {
+	pim, err := asObject(m, path)
+	if err != nil {
+		return nil, err
 	}
 
-	p := &GeoLocation{}
+	p := &GeoLocation{keys: newKeySet(pim)}
 	for k, v := range pim {
 		switch k {
 		case "latitude":
-			p.Latitude = v.(float64)
+			if p.Latitude, err = asNumber(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		case "longitude":
-			p.Longitude = v.(float64)
+			if p.Longitude, err = asNumber(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		default:
-			return nil, fmt.Errorf("map contains unsupported key: %s", k)
+			if err := d.unknownKey(path, k, v, &p.Extra); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return p, nil
 }
+
+// wire returns GeoLocation in the format of the API.
+func (p *GeoLocation) wire() map[string]interface{} {
+	w := newWireObject(p.keys)
+	w.set("latitude", p.Latitude, p.Latitude != 0)
+	w.set("longitude", p.Longitude, p.Longitude != 0)
+	return withExtra(w.m, p.Extra)
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"fmt"
+)
+
+// hierarchyParentKinds maps a resource kind to the kinds of its parents
+// followed when walking vSphere inventory, i.e. VirtualMachine to HostSystem
+// to ClusterComputeResource to Datacenter to vCenter adapter instance. The
+// standalone hosts have no cluster and belong to a Datacenter directly.
+var hierarchyParentKinds = map[string][]string{
+	"VirtualMachine":         {"HostSystem", "Datastore"},
+	"HostSystem":             {"ClusterComputeResource", "Datacenter"},
+	"ClusterComputeResource": {"Datacenter"},
+	"Datacenter":             {"VMwareAdapter Instance"},
+}
+
+// ResourceNode is a node in a tree of related resources.
+type ResourceNode struct {
+	Resource *Resource       `json:"resource,omitempty"`
+	Parents  []*ResourceNode `json:"parents,omitempty"`
+}
+
+// Placement is the location of a resource in vSphere inventory.
+type Placement struct {
+	Host       string   `json:"host,omitempty"`
+	Cluster    string   `json:"cluster,omitempty"`
+	Datacenter string   `json:"datacenter,omitempty"`
+	VCenter    string   `json:"vcenter,omitempty"`
+	Datastores []string `json:"datastores,omitempty"`
+}
+
+// GetResourceHierarchy returns the tree of parents of the resource with the
+// provided identifier. The walk follows vSphere inventory, i.e.
+// VM -> Host -> Cluster -> Datacenter -> vCenter, and the datastores of VMs.
+func (c *Client) GetResourceHierarchy(id string) (*ResourceNode, error) {
+	r, err := c.GetResource(id)
+	if err != nil {
+		return nil, err
+	}
+	root := &ResourceNode{Resource: r}
+	visited := map[string]bool{r.ID: true}
+	if err := c.walkResourceHierarchy(root, visited); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func (c *Client) walkResourceHierarchy(node *ResourceNode, visited map[string]bool) error {
+	if node.Resource.Key == nil {
+		return nil
+	}
+	kinds, exists := hierarchyParentKinds[node.Resource.Key.ResourceKindKey]
+	if !exists {
+		return nil
+	}
+	parents, err := c.GetRelationships(node.Resource.ID, RelationshipParent)
+	if err != nil {
+		return fmt.Errorf("failed fetching parents of %s: %s", node.Resource.ID, err)
+	}
+	for _, parent := range parents {
+		if parent.Key == nil || visited[parent.ID] {
+			continue
+		}
+		if !containsString(kinds, parent.Key.ResourceKindKey) {
+			continue
+		}
+		visited[parent.ID] = true
+		child := &ResourceNode{Resource: parent}
+		if err := c.walkResourceHierarchy(child, visited); err != nil {
+			return err
+		}
+		node.Parents = append(node.Parents, child)
+	}
+	return nil
+}
+
+// Ancestors returns the resources of the provided kind found
+// among the parents of the node, at any depth.
+func (n *ResourceNode) Ancestors(resourceKind string) []*Resource {
+	resources := []*Resource{}
+	for _, parent := range n.Parents {
+		if parent.Resource.Key != nil && parent.Resource.Key.ResourceKindKey == resourceKind {
+			resources = append(resources, parent.Resource)
+		}
+		resources = append(resources, parent.Ancestors(resourceKind)...)
+	}
+	return resources
+}
+
+// Placement returns the host, cluster, datacenter, vCenter and datastores
+// the resource of the node belongs to.
+func (n *ResourceNode) Placement() *Placement {
+	p := &Placement{}
+	if items := n.Ancestors("HostSystem"); len(items) > 0 {
+		p.Host = items[0].Key.Name
+	}
+	if items := n.Ancestors("ClusterComputeResource"); len(items) > 0 {
+		p.Cluster = items[0].Key.Name
+	}
+	if items := n.Ancestors("Datacenter"); len(items) > 0 {
+		p.Datacenter = items[0].Key.Name
+	}
+	if items := n.Ancestors("VMwareAdapter Instance"); len(items) > 0 {
+		p.VCenter = items[0].Key.Name
+	}
+	for _, item := range n.Ancestors("Datastore") {
+		p.Datastores = append(p.Datastores, item.Key.Name)
+	}
+	return p
+}
+
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
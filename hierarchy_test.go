@@ -0,0 +1,151 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	. "github.com/greenpau/go-vrop/internal/server"
+	"reflect"
+	"testing"
+)
+
+func newHierarchyMockClient(t *testing.T) (*Client, *MockTestServer) {
+	return newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/resources/vm-1": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "resource_vm.json"},
+		},
+		"/suite-api/api/resources/host-2": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "resource_host_standalone.json"},
+		},
+		"/suite-api/api/resources/vm-1/relationships": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "relationships_vm.json"},
+		},
+		"/suite-api/api/resources/host-1/relationships": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "relationships_host.json"},
+		},
+		"/suite-api/api/resources/host-2/relationships": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "relationships_host_standalone.json"},
+		},
+		"/suite-api/api/resources/cluster-1/relationships": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "relationships_cluster.json"},
+		},
+		"/suite-api/api/resources/dc-1/relationships": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "relationships_datacenter.json"},
+		},
+	})
+}
+
+func TestGetResourceHierarchy(t *testing.T) {
+	testcases := []struct {
+		name      string
+		id        string
+		placement *Placement
+	}{
+		{
+			name: "virtual machine on clustered host",
+			id:   "vm-1",
+			placement: &Placement{
+				Host:       "esx01.example.com",
+				Cluster:    "cluster01",
+				Datacenter: "dc01",
+				VCenter:    "vcenter01",
+				Datastores: []string{"datastore01"},
+			},
+		},
+		{
+			name: "standalone host",
+			id:   "host-2",
+			placement: &Placement{
+				Datacenter: "dc01",
+				VCenter:    "vcenter01",
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli, server := newHierarchyMockClient(t)
+			defer server.Close()
+			defer cli.Close()
+
+			root, err := cli.GetResourceHierarchy(tc.id)
+			if err != nil {
+				t.Fatalf("failed getting resource hierarchy: %s", err)
+			}
+			if root.Resource.ID != tc.id {
+				t.Fatalf("expected root %s, got %s", tc.id, root.Resource.ID)
+			}
+			if got := root.Placement(); !reflect.DeepEqual(got, tc.placement) {
+				t.Fatalf("unexpected placement:\ngot:  %+v\nwant: %+v", got, tc.placement)
+			}
+		})
+	}
+}
+
+func TestResourceNodeAncestors(t *testing.T) {
+	cli, server := newHierarchyMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	root, err := cli.GetResourceHierarchy("vm-1")
+	if err != nil {
+		t.Fatalf("failed getting resource hierarchy: %s", err)
+	}
+	// The parents of other kinds, e.g. ResourcePool, are not followed.
+	if len(root.Parents) != 2 {
+		t.Fatalf("expected 2 parents, got %d", len(root.Parents))
+	}
+	if items := root.Ancestors("ResourcePool"); len(items) != 0 {
+		t.Fatalf("expected no resource pools, got %d", len(items))
+	}
+	items := root.Ancestors("Datacenter")
+	if len(items) != 1 || items[0].ID != "dc-1" {
+		t.Fatalf("expected datacenter dc-1, got %v", items)
+	}
+}
+
+func TestGetRelationships(t *testing.T) {
+	cli, server := newHierarchyMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	resources, err := cli.GetRelationships("vm-1", RelationshipParent)
+	if err != nil {
+		t.Fatalf("failed getting relationships: %s", err)
+	}
+	var ids []string
+	for _, r := range resources {
+		ids = append(ids, r.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"host-1", "ds-1", "pool-1"}) {
+		t.Fatalf("unexpected related resources: %v", ids)
+	}
+
+	var uris []string
+	for _, req := range server.Requests() {
+		if req.RequestURI != "/suite-api/api/auth/token/acquire" {
+			uris = append(uris, req.RequestURI)
+		}
+	}
+	want := []string{"/suite-api/api/resources/vm-1/relationships?page=0&pageSize=100&relationshipType=PARENT"}
+	if !reflect.DeepEqual(uris, want) {
+		t.Fatalf("unexpected requests:\ngot:  %v\nwant: %v", uris, want)
+	}
+
+	if _, err := cli.GetRelationships("vm-1", "SIBLING"); err == nil {
+		t.Fatalf("expected error on unsupported relationship type")
+	}
+	if _, err := cli.GetRelationships("", RelationshipParent); err == nil {
+		t.Fatalf("expected error on empty resource id")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// HostSystem is an ESXi host.
+type HostSystem struct {
+	InventoryItem
+	// The hardware of the host.
+	Vendor   string  `json:"vendor,omitempty"`
+	Model    string  `json:"model,omitempty"`
+	CPUModel string  `json:"cpu_model,omitempty"`
+	CPUCores int     `json:"cpu_cores,omitempty"`
+	MemoryMB float64 `json:"memory_mb,omitempty"`
+	// The version of ESXi running on the host.
+	Version string `json:"version,omitempty"`
+	// The power, connection and maintenance state of the host, e.g.
+	// poweredOn, connected, notInMaintenance.
+	PowerState       string `json:"power_state,omitempty"`
+	ConnectionState  string `json:"connection_state,omitempty"`
+	MaintenanceState string `json:"maintenance_state,omitempty"`
+	// The names of the parents of the host.
+	ParentCluster    string `json:"parent_cluster,omitempty"`
+	ParentDatacenter string `json:"parent_datacenter,omitempty"`
+	ParentVCenter    string `json:"parent_vcenter,omitempty"`
+}
+
+// GetHostSystems returns a list of HostSystem instances, selected by the
+// options of GetResources, e.g. name or tags.
+func (c *Client) GetHostSystems(opts map[string]interface{}) ([]*HostSystem, error) {
+	hosts := []*HostSystem{}
+	err := c.getInventory("VMWARE", "HostSystem", opts, func() inventoryResource {
+		m := &HostSystem{}
+		hosts = append(hosts, m)
+		return m
+	})
+	return hosts, err
+}
+
+// parse populates the typed fields of HostSystem from its properties.
+func (m *HostSystem) parse(r *Resource) []error {
+	p := &propertyParser{kind: "HostSystem"}
+	for k, v := range m.Properties {
+		if v == "" {
+			continue
+		}
+		switch k {
+		case "hardware|vendor":
+			m.Vendor = v
+		case "hardware|vendorModel":
+			m.Model = v
+		case "hardware|cpuInfo|cpuModel":
+			m.CPUModel = v
+		case "hardware|cpuInfo|numCpuCores":
+			m.CPUCores = p.parseInt(k, v)
+		case "hardware|memorySize":
+			m.MemoryMB = p.parseFloat(k, v) / 1024
+		case "summary|version":
+			m.Version = v
+		case "runtime|powerState":
+			m.PowerState = v
+		case "runtime|connectionState":
+			m.ConnectionState = v
+		case "runtime|maintenanceState":
+			m.MaintenanceState = v
+		case "summary|parentCluster":
+			m.ParentCluster = v
+		case "summary|parentDatacenter":
+			m.ParentDatacenter = v
+		case "summary|parentVcenter":
+			m.ParentVCenter = v
+		}
+	}
+	return p.errors
+}
+
+// ToJSONString serializes HostSystem to a string.
+func (m *HostSystem) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
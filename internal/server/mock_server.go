@@ -26,6 +26,8 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // MockTestServerInstance is an instance of a mock web server.
@@ -41,12 +43,44 @@ type MockTestServerInstance struct {
 type MockTestServer struct {
 	NonTLS *MockTestServerInstance
 	TLS    *MockTestServerInstance
+
+	mu       sync.Mutex
+	requests []*MockTestRequest
+	hits     map[*MockTestEndpoint]int
 }
 
-// MockTestEndpoint is a mock API endpoint.
+// MockTestEndpoint is a mock API endpoint. The endpoint responds to GET
+// requests, unless Method is set.
 type MockTestEndpoint struct {
+	Method     string
 	RequestURI string
 	FileName   string
+	// Delay is the time the endpoint waits before responding, unless
+	// the request is canceled.
+	Delay time.Duration
+	// Query, when set, must match the query of the request, e.g.
+	// page=1&pageSize=100, for the endpoint to respond.
+	Query string
+	// Limit, when set, is the number of requests the endpoint responds to.
+	// The following requests are answered by the next matching endpoint,
+	// if any, e.g. to fail a request after the successful ones.
+	Limit int
+}
+
+// MockTestRequest is a request received by MockTestServer.
+type MockTestRequest struct {
+	Method     string
+	RequestURI string
+	Body       []byte
+}
+
+// Requests returns the requests received by MockTestServer.
+func (srv *MockTestServer) Requests() []*MockTestRequest {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	requests := make([]*MockTestRequest, len(srv.requests))
+	copy(requests, srv.requests)
+	return requests
 }
 
 // Close closes running instances of MockTestServerInstance, if any.
@@ -66,6 +100,7 @@ func NewMockTestServer(log *zap.Logger, pathMap map[string][]*MockTestEndpoint,
 	mts := &MockTestServer{
 		NonTLS: &MockTestServerInstance{},
 		TLS:    &MockTestServerInstance{},
+		hits:   make(map[*MockTestEndpoint]int),
 	}
 	serverEndpoints := map[string][]*MockTestEndpoint{
 		"/v2/siem/all": []*MockTestEndpoint{
@@ -118,10 +153,18 @@ func NewMockTestServer(log *zap.Logger, pathMap map[string][]*MockTestEndpoint,
 			return
 		}
 
-		if req.Method != "GET" {
-			http.Error(w, "Bad Request, expecting GET", http.StatusBadRequest)
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		mts.mu.Lock()
+		mts.requests = append(mts.requests, &MockTestRequest{
+			Method:     req.Method,
+			RequestURI: req.RequestURI,
+			Body:       body,
+		})
+		mts.mu.Unlock()
 
 		if strings.HasSuffix(req.URL.Path, "/empty_response") {
 			panic("")
@@ -145,7 +188,45 @@ func NewMockTestServer(log *zap.Logger, pathMap map[string][]*MockTestEndpoint,
 			http.Error(w, string(fc), http.StatusNotFound)
 			return
 		}
-		fp = fmt.Sprintf("%s/%s", dataDir, endpoints[0].FileName)
+		var endpoint *MockTestEndpoint
+		for _, e := range endpoints {
+			method := e.Method
+			if method == "" {
+				method = "GET"
+			}
+			if e.Query != "" && e.Query != req.URL.RawQuery {
+				continue
+			}
+			if method != req.Method {
+				continue
+			}
+			mts.mu.Lock()
+			exhausted := e.Limit > 0 && mts.hits[e] >= e.Limit
+			if !exhausted {
+				mts.hits[e]++
+			}
+			mts.mu.Unlock()
+			if !exhausted {
+				endpoint = e
+				break
+			}
+		}
+		if endpoint == nil {
+			http.Error(w, fmt.Sprintf("Bad Request, unexpected %s", req.Method), http.StatusBadRequest)
+			return
+		}
+		if endpoint.Delay > 0 {
+			select {
+			case <-time.After(endpoint.Delay):
+			case <-req.Context().Done():
+				return
+			}
+		}
+		if endpoint.FileName == "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		fp = fmt.Sprintf("%s/%s", dataDir, endpoint.FileName)
 		fc, err = ioutil.ReadFile(fp)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -0,0 +1,142 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// InventoryItem holds the fields common to the typed inventory resources,
+// e.g. HostSystem or Datastore.
+type InventoryItem struct {
+	ID               string            `json:"id,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	VMEntityName     string            `json:"vm_entity_name,omitempty"`
+	VMEntityObjectID string            `json:"vm_entity_object_id,omitempty"`
+	VMEntityVCID     string            `json:"vm_entity_vcid,omitempty"`
+	CreatedAt        time.Time         `json:"created_at,omitempty"`
+	LastSeenAt       time.Time         `json:"last_seen_at,omitempty"`
+	Properties       map[string]string `json:"properties,omitempty"`
+	Errors           []string          `json:"errors,omitempty"`
+}
+
+// inventoryResource is a typed inventory resource.
+type inventoryResource interface {
+	// item returns the fields common to the inventory resources.
+	item() *InventoryItem
+	// parse populates the typed fields from the identifiers and the
+	// properties of the resource. It returns the errors encountered
+	// during parsing.
+	parse(r *Resource) []error
+}
+
+func (m *InventoryItem) item() *InventoryItem {
+	return m
+}
+
+// getInventory lists the resources of the provided adapter kind and
+// resource kind, selected by the options of GetResources, e.g. name or
+// tags. For each resource, it populates the typed inventory resource
+// returned by newResource. The properties of the resources are retrieved
+// in bulk, when supported by the server.
+func (c *Client) getInventory(adapterKind, resourceKind string, opts map[string]interface{}, newResource func() inventoryResource) error {
+	filter := map[string]interface{}{
+		"adapter_kind":  adapterKind,
+		"resource_kind": resourceKind,
+	}
+	for k, v := range opts {
+		switch k {
+		case "adapter_kind", "resource_kind":
+			return unsupportedOption(k)
+		}
+		filter[k] = v
+	}
+	resources, err := c.GetResources(filter)
+	if err != nil {
+		return err
+	}
+
+	ids := []string{}
+	for _, r := range resources {
+		ids = append(ids, r.ID)
+	}
+	properties, propErr := c.GetResourcesProperties(ids)
+
+	now := time.Now().UTC()
+	for _, r := range resources {
+		p := newResource()
+		m := p.item()
+		m.ID = r.ID
+		m.CreatedAt = r.CreationTime
+		m.LastSeenAt = now
+		m.Properties = make(map[string]string)
+		if r.Key != nil {
+			m.Name = r.Key.Name
+			for _, entry := range r.Key.ResourceIdentifiers {
+				switch entry.Key {
+				case "VMEntityName":
+					m.VMEntityName = entry.Value
+				case "VMEntityObjectID":
+					m.VMEntityObjectID = entry.Value
+				case "VMEntityVCID":
+					m.VMEntityVCID = entry.Value
+				}
+			}
+		}
+		if propErr != nil {
+			m.Errors = append(m.Errors, fmt.Sprintf("failed fetching %s properties: %s", resourceKind, propErr))
+		}
+		for k, v := range properties[r.ID] {
+			m.Properties[k] = v
+		}
+		for _, err := range p.parse(r) {
+			m.Errors = append(m.Errors, err.Error())
+		}
+	}
+	return nil
+}
+
+// propertyParser converts the properties of an inventory resource to
+// typed values, and collects the conversion errors.
+type propertyParser struct {
+	kind   string
+	errors []error
+}
+
+func (p *propertyParser) parseInt(k, v string) int {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		p.errors = append(p.errors, fmt.Errorf("failed parsing %s property %s: %s", p.kind, k, err))
+	}
+	return n
+}
+
+func (p *propertyParser) parseFloat(k, v string) float64 {
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		p.errors = append(p.errors, fmt.Errorf("failed parsing %s property %s: %s", p.kind, k, err))
+	}
+	return n
+}
+
+func (p *propertyParser) parseBool(k, v string) bool {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		p.errors = append(p.errors, fmt.Errorf("failed parsing %s property %s: %s", p.kind, k, err))
+	}
+	return b
+}
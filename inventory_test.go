@@ -0,0 +1,89 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	. "github.com/greenpau/go-vrop/internal/server"
+	"strings"
+	"testing"
+)
+
+func TestGetHostSystems(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/versions/current": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "versions_current.json"},
+		},
+		"/suite-api/api/resources": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "host_systems.json"},
+		},
+		"/suite-api/api/resources/properties": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "POST", FileName: "host_systems_properties.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	hosts, err := cli.GetHostSystems(map[string]interface{}{"name": "esx"})
+	if err != nil {
+		t.Fatalf("failed getting hosts: %s", err)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("expected 2 hosts, got %d", len(hosts))
+	}
+
+	h := hosts[0]
+	if h.Name != "esx01.example.com" || h.VMEntityObjectID != "host-21" || h.VMEntityVCID == "" {
+		t.Fatalf("unexpected host identifiers: %+v", h.InventoryItem)
+	}
+	if h.Vendor != "Dell Inc." || h.CPUCores != 40 || h.MemoryMB != 786103 || h.Version != "7.0.1" {
+		t.Fatalf("unexpected host hardware: %+v", h)
+	}
+	if h.ParentCluster != "cluster-01" || h.ParentDatacenter != "dc-01" || h.ParentVCenter != "vcenter-01" {
+		t.Fatalf("unexpected host parents: %+v", h)
+	}
+	if len(h.Errors) != 0 {
+		t.Fatalf("unexpected host errors: %v", h.Errors)
+	}
+	if len(hosts[1].Errors) != 1 || !strings.Contains(hosts[1].Errors[0], "hardware|cpuInfo|numCpuCores") {
+		t.Fatalf("expected property parsing error, got: %v", hosts[1].Errors)
+	}
+
+	// The options are applied to the listing, and the properties are
+	// retrieved in a single call.
+	var listings, propertyCalls int
+	for _, req := range server.Requests() {
+		switch {
+		case strings.HasPrefix(req.RequestURI, "/suite-api/api/resources?"):
+			listings++
+			for _, param := range []string{"adapterKind=VMWARE", "resourceKind=HostSystem", "name=esx"} {
+				if !strings.Contains(req.RequestURI, param) {
+					t.Fatalf("expected %s in request %s", param, req.RequestURI)
+				}
+			}
+		case strings.HasPrefix(req.RequestURI, "/suite-api/api/resources/"):
+			propertyCalls++
+			if req.Method != "POST" || !strings.Contains(string(req.Body), h.ID) {
+				t.Fatalf("unexpected properties request: %s %s %s", req.Method, req.RequestURI, req.Body)
+			}
+		}
+	}
+	if listings != 1 || propertyCalls != 1 {
+		t.Fatalf("expected 1 listing and 1 properties call, got %d and %d", listings, propertyCalls)
+	}
+
+	if _, err := cli.GetHostSystems(map[string]interface{}{"resource_kind": "Datastore"}); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+}
@@ -1,7 +1,7 @@
 package vrop
 
 import (
-	"fmt"
+	"encoding/json"
 )
 
 // Link is a reference to an object.
@@ -19,31 +19,74 @@ type Link struct {
 	// RELATED: Used to represent that this link points to an object related to the link's parent
 	// SELF: Used to represent that this link points to more information of the link's parent tag/object
 	Relation string `json:"rel,omitempty"`
+	// Extra holds the values of the keys not supported by this package,
+	// when decoded in lenient mode.
+	Extra map[string]json.RawMessage `json:"-"`
+	// keys are the keys found in the response the Link was unpacked from.
+	keys keySet
 }
 
-func unpackLink(m interface{}) (*Link, error) {
-	var pim map[string]interface{}
-
-	switch m.(type) {
-	case map[string]interface{}:
-		pim = m.(map[string]interface{})
-	default:
-		return nil, fmt.Errorf("map not found")
+func unpackLink(m interface{}, path string, d *decoder) (*Link, error) {
+	pim, err := asObject(m, path)
+	if err != nil {
+		return nil, err
 	}
 
-	p := &Link{}
+	p := &Link{keys: newKeySet(pim)}
 	for k, v := range pim {
 		switch k {
 		case "href":
-			p.Href = v.(string)
+			if p.Href, err = asString(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		case "name":
-			p.Name = v.(string)
+			if p.Name, err = asString(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		case "rel":
-			p.Relation = v.(string)
+			if p.Relation, err = asString(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		default:
-			return nil, fmt.Errorf("map contains unsupported key: %s", k)
+			if err := d.unknownKey(path, k, v, &p.Extra); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return p, nil
 }
+
+// unpackLinks unpacks the array of links at the path.
+func unpackLinks(m interface{}, path string, d *decoder) ([]*Link, error) {
+	items, err := asArray(m, path)
+	if err != nil {
+		return nil, err
+	}
+	links := []*Link{}
+	for i, item := range items {
+		link, err := unpackLink(item, indexPath(path, i), d)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// wire returns Link in the format of the API.
+func (p *Link) wire() map[string]interface{} {
+	w := newWireObject(p.keys)
+	w.set("href", p.Href, p.Href != "")
+	w.set("name", p.Name, p.Name != "")
+	w.set("rel", p.Relation, p.Relation != "")
+	return withExtra(w.m, p.Extra)
+}
+
+func wireLinks(links []*Link) []interface{} {
+	items := []interface{}{}
+	for _, link := range links {
+		items = append(items, link.wire())
+	}
+	return items
+}
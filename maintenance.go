@@ -0,0 +1,250 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaintenanceSchedule is a recurring or one-time window during which
+// the resources are in maintenance.
+type MaintenanceSchedule struct {
+	// Identifier of the MaintenanceSchedule, assigned by the server.
+	ID string `json:"id,omitempty"`
+	// Name and description of the MaintenanceSchedule.
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// The time zone of the schedule, e.g. America/New_York.
+	TimeZone string `json:"timeZone,omitempty"`
+	// The start of the first maintenance window.
+	StartTime Timestamp `json:"startTime"`
+	// The length of a maintenance window in minutes.
+	DurationMinutes int `json:"duration,omitempty"`
+	// The recurrence of the schedule, i.e. ONCE, DAILY, WEEKLY, MONTHLY.
+	Recurrence string `json:"recurrence,omitempty"`
+	// The identifiers of the resources put in maintenance.
+	ResourceIDs []string `json:"resourceIds,omitempty"`
+}
+
+// MaintenanceSchedulesResponse is a response with maintenance schedules.
+type MaintenanceSchedulesResponse struct {
+	Page      *PageInfo              `json:"pageInfo,omitempty"`
+	Links     []*Link                `json:"links,omitempty"`
+	Schedules []*MaintenanceSchedule `json:"maintenanceSchedules,omitempty"`
+}
+
+// MaintenanceError is the error of putting resources in or taking them out
+// of maintenance. It names the resources that failed, while the others
+// were processed.
+type MaintenanceError struct {
+	// The action that failed, i.e. mark or unmark.
+	Action string
+	// The identifiers of the resources that failed, and their errors.
+	IDs    []string
+	Errors []error
+}
+
+// Error returns the identifiers of the failed resources and their errors.
+func (e *MaintenanceError) Error() string {
+	var items []string
+	for i, id := range e.IDs {
+		items = append(items, fmt.Sprintf("%s: %s", id, e.Errors[i]))
+	}
+	return fmt.Sprintf("failed to %s maintenance for resources: %s",
+		e.Action, strings.Join(items, "; "))
+}
+
+func (e *MaintenanceError) add(id string, err error) {
+	e.IDs = append(e.IDs, id)
+	e.Errors = append(e.Errors, err)
+}
+
+// MarkMaintenance puts the resources with the provided identifiers in
+// maintenance, so that no alerts are raised for them. When the duration
+// is zero, the resources stay in maintenance until unmarked. A failure of
+// a resource does not stop the others, and the returned MaintenanceError
+// names the resources that failed.
+func (c *Client) MarkMaintenance(ids []string, duration time.Duration) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("no resource ids to mark maintenance")
+	}
+	if duration < 0 {
+		return fmt.Errorf("invalid maintenance duration: %s", duration)
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	if duration > 0 {
+		minutes := int(duration / time.Minute)
+		if minutes < 1 {
+			minutes = 1
+		}
+		params["duration"] = strconv.Itoa(minutes)
+	}
+	merr := &MaintenanceError{Action: "mark"}
+	for _, id := range ids {
+		if _, err := c.request("PUT", "resources/"+id+"/maintained", params); err != nil {
+			merr.add(id, err)
+		}
+	}
+	if len(merr.IDs) > 0 {
+		return merr
+	}
+	return nil
+}
+
+// UnmarkMaintenance takes the resources with the provided identifiers
+// out of maintenance. Like MarkMaintenance, it processes all the resources
+// and returns MaintenanceError naming the ones that failed.
+func (c *Client) UnmarkMaintenance(ids []string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("no resource ids to unmark maintenance")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	merr := &MaintenanceError{Action: "unmark"}
+	for _, id := range ids {
+		if _, err := c.request("DELETE", "resources/"+id+"/maintained", params); err != nil {
+			merr.add(id, err)
+		}
+	}
+	if len(merr.IDs) > 0 {
+		return merr
+	}
+	return nil
+}
+
+// GetMaintenanceSchedules returns a list of maintenance schedules.
+func (c *Client) GetMaintenanceSchedules() ([]*MaintenanceSchedule, error) {
+	schedules := []*MaintenanceSchedule{}
+	if err := c.authenticate(); err != nil {
+		return schedules, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := make(map[string]string)
+		params["page"] = strconv.Itoa(pageOffset)
+		params["pageSize"] = strconv.Itoa(pageSize)
+		b, err := c.request("GET", "maintenanceschedules", params)
+		if err != nil {
+			return schedules, err
+		}
+
+		resp := &MaintenanceSchedulesResponse{}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return schedules, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+
+		schedules = append(schedules, resp.Schedules...)
+
+		if len(resp.Schedules) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return schedules, nil
+}
+
+// CreateMaintenanceSchedule creates the maintenance schedule and returns
+// it with the identifier assigned by the server.
+func (c *Client) CreateMaintenanceSchedule(s *MaintenanceSchedule) (*MaintenanceSchedule, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.requestWithPayload("POST", "maintenanceschedules", params, s)
+	if err != nil {
+		return nil, err
+	}
+	created := &MaintenanceSchedule{}
+	if err := json.Unmarshal(b, created); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return created, nil
+}
+
+// UpdateMaintenanceSchedule updates the maintenance schedule.
+func (c *Client) UpdateMaintenanceSchedule(s *MaintenanceSchedule) (*MaintenanceSchedule, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	if s.ID == "" {
+		return nil, fmt.Errorf("empty maintenance schedule id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.requestWithPayload("PUT", "maintenanceschedules", params, s)
+	if err != nil {
+		return nil, err
+	}
+	updated := &MaintenanceSchedule{}
+	if err := json.Unmarshal(b, updated); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return updated, nil
+}
+
+// DeleteMaintenanceSchedule deletes the maintenance schedule with the
+// provided identifier.
+func (c *Client) DeleteMaintenanceSchedule(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty maintenance schedule id")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	if _, err := c.request("DELETE", "maintenanceschedules/"+id, params); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *MaintenanceSchedule) validate() error {
+	if s == nil {
+		return fmt.Errorf("nil maintenance schedule")
+	}
+	if s.Name == "" {
+		return fmt.Errorf("maintenance schedule name is empty")
+	}
+	if s.StartTime.IsZero() {
+		return fmt.Errorf("maintenance schedule %s has no start time", s.Name)
+	}
+	if s.DurationMinutes < 1 {
+		return fmt.Errorf("maintenance schedule %s has invalid duration: %d", s.Name, s.DurationMinutes)
+	}
+	switch s.Recurrence {
+	case "", "ONCE", "DAILY", "WEEKLY", "MONTHLY":
+	default:
+		return fmt.Errorf("maintenance schedule %s has unsupported recurrence: %s", s.Name, s.Recurrence)
+	}
+	return nil
+}
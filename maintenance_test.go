@@ -0,0 +1,249 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"errors"
+	. "github.com/greenpau/go-vrop/internal/server"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarkMaintenance(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/resources/r1/maintained": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "PUT"},
+		},
+		"/suite-api/api/resources/r3/maintained": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "PUT"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	// The resource r2 fails, and r3 is still put in maintenance.
+	err := cli.MarkMaintenance([]string{"r1", "r2", "r3"}, 2*time.Hour)
+	var merr *MaintenanceError
+	if !errors.As(err, &merr) {
+		t.Fatalf("expected MaintenanceError, got: %v", err)
+	}
+	if !reflect.DeepEqual(merr.IDs, []string{"r2"}) {
+		t.Fatalf("unexpected failed resources: %v", merr.IDs)
+	}
+	if !strings.HasPrefix(err.Error(), "failed to mark maintenance for resources: r2: ") {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var uris []string
+	for _, req := range server.Requests() {
+		if req.Method == "PUT" {
+			uris = append(uris, req.RequestURI)
+		}
+	}
+	want := []string{
+		"/suite-api/api/resources/r1/maintained?duration=120",
+		"/suite-api/api/resources/r2/maintained?duration=120",
+		"/suite-api/api/resources/r3/maintained?duration=120",
+	}
+	if !reflect.DeepEqual(uris, want) {
+		t.Fatalf("unexpected requests:\ngot:  %v\nwant: %v", uris, want)
+	}
+
+	if err := cli.MarkMaintenance([]string{"r1", "r3"}, 0); err != nil {
+		t.Fatalf("failed to mark maintenance: %s", err)
+	}
+}
+
+func TestUnmarkMaintenance(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/resources/r1/maintained": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "DELETE"},
+		},
+		"/suite-api/api/resources/r3/maintained": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "DELETE"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	if err := cli.UnmarkMaintenance(nil); err == nil {
+		t.Fatalf("expected error for empty resource ids, got none")
+	}
+
+	// The resources r2 and r4 fail, and r3 is still taken out of
+	// maintenance.
+	err := cli.UnmarkMaintenance([]string{"r1", "r2", "r3", "r4"})
+	var merr *MaintenanceError
+	if !errors.As(err, &merr) {
+		t.Fatalf("expected MaintenanceError, got: %v", err)
+	}
+	if merr.Action != "unmark" || !reflect.DeepEqual(merr.IDs, []string{"r2", "r4"}) {
+		t.Fatalf("unexpected failed resources: %s %v", merr.Action, merr.IDs)
+	}
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "failed to unmark maintenance for resources: r2: ") ||
+		!strings.Contains(msg, "; r4: ") || strings.Count(msg, "r2") != 1 {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+
+	var uris []string
+	for _, req := range server.Requests() {
+		if req.Method == "DELETE" {
+			uris = append(uris, strings.TrimSuffix(req.RequestURI, "?"))
+		}
+	}
+	want := []string{
+		"/suite-api/api/resources/r1/maintained",
+		"/suite-api/api/resources/r2/maintained",
+		"/suite-api/api/resources/r3/maintained",
+		"/suite-api/api/resources/r4/maintained",
+	}
+	if !reflect.DeepEqual(uris, want) {
+		t.Fatalf("unexpected requests:\ngot:  %v\nwant: %v", uris, want)
+	}
+
+	if err := cli.UnmarkMaintenance([]string{"r1", "r3"}); err != nil {
+		t.Fatalf("failed to unmark maintenance: %s", err)
+	}
+}
+
+func TestMaintenanceSchedules(t *testing.T) {
+	id := "e4d3c2b1-a098-4f7e-8d6c-5b4a39281706"
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/maintenanceschedules": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "maintenance_schedules.json"},
+			&MockTestEndpoint{Method: "POST", FileName: "maintenance_schedule.json"},
+			&MockTestEndpoint{Method: "PUT", FileName: "maintenance_schedule.json"},
+		},
+		"/suite-api/api/maintenanceschedules/" + id: []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "DELETE"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	schedules, err := cli.GetMaintenanceSchedules()
+	if err != nil {
+		t.Fatalf("failed getting maintenance schedules: %s", err)
+	}
+	if len(schedules) != 1 || schedules[0].ID != id || schedules[0].DurationMinutes != 120 {
+		t.Fatalf("unexpected maintenance schedules: %+v", schedules)
+	}
+
+	start := time.Date(2020, 12, 12, 21, 0, 0, 0, time.UTC)
+	s := &MaintenanceSchedule{
+		Name:            "weekly-patching",
+		TimeZone:        "America/New_York",
+		StartTime:       NewTimestamp(start),
+		DurationMinutes: 120,
+		Recurrence:      "WEEKLY",
+		ResourceIDs:     []string{"3b9d2c1a-7e4f-4a8b-9c6d-5e4f3a2b1c0d"},
+	}
+	created, err := cli.CreateMaintenanceSchedule(s)
+	if err != nil {
+		t.Fatalf("failed creating maintenance schedule: %s", err)
+	}
+	if created.ID != id {
+		t.Fatalf("unexpected created maintenance schedule id: %s", created.ID)
+	}
+	payload := map[string]interface{}{}
+	req := lastRequest(t, server, &payload)
+	if req.Method != "POST" {
+		t.Fatalf("unexpected request method: %s", req.Method)
+	}
+	if _, exists := payload["id"]; exists || payload["startTime"] != float64(start.UnixNano()/1e6) ||
+		payload["duration"] != float64(120) || payload["recurrence"] != "WEEKLY" {
+		t.Fatalf("unexpected create payload: %s", req.Body)
+	}
+
+	if _, err := cli.UpdateMaintenanceSchedule(s); err == nil {
+		t.Fatalf("expected error for maintenance schedule without id, got none")
+	}
+	created.DurationMinutes = 180
+	if _, err := cli.UpdateMaintenanceSchedule(created); err != nil {
+		t.Fatalf("failed updating maintenance schedule: %s", err)
+	}
+	updated := &MaintenanceSchedule{}
+	req = lastRequest(t, server, updated)
+	if req.Method != "PUT" || updated.ID != id || updated.DurationMinutes != 180 {
+		t.Fatalf("unexpected update request: %s %s", req.Method, req.Body)
+	}
+
+	if err := cli.DeleteMaintenanceSchedule(id); err != nil {
+		t.Fatalf("failed deleting maintenance schedule: %s", err)
+	}
+	req = lastRequest(t, server, nil)
+	if req.Method != "DELETE" || !strings.HasPrefix(req.RequestURI, "/suite-api/api/maintenanceschedules/"+id) {
+		t.Fatalf("unexpected request: %s %s", req.Method, req.RequestURI)
+	}
+	if err := cli.DeleteMaintenanceSchedule(""); err == nil {
+		t.Fatalf("expected error for empty maintenance schedule id, got none")
+	}
+}
+
+func TestMaintenanceScheduleValidate(t *testing.T) {
+	start := NewTimestamp(time.Date(2020, 12, 12, 21, 0, 0, 0, time.UTC))
+	testcases := []struct {
+		name      string
+		schedule  *MaintenanceSchedule
+		shouldErr bool
+	}{
+		{
+			name:     "one-time schedule",
+			schedule: &MaintenanceSchedule{Name: "m1", StartTime: start, DurationMinutes: 60},
+		},
+		{
+			name:     "monthly schedule",
+			schedule: &MaintenanceSchedule{Name: "m1", StartTime: start, DurationMinutes: 1, Recurrence: "MONTHLY"},
+		},
+		{
+			name:      "nil schedule",
+			shouldErr: true,
+		},
+		{
+			name:      "schedule without name",
+			schedule:  &MaintenanceSchedule{StartTime: start, DurationMinutes: 60},
+			shouldErr: true,
+		},
+		{
+			name:      "schedule without start time",
+			schedule:  &MaintenanceSchedule{Name: "m1", DurationMinutes: 60},
+			shouldErr: true,
+		},
+		{
+			name:      "schedule without duration",
+			schedule:  &MaintenanceSchedule{Name: "m1", StartTime: start},
+			shouldErr: true,
+		},
+		{
+			name:      "schedule with unsupported recurrence",
+			schedule:  &MaintenanceSchedule{Name: "m1", StartTime: start, DurationMinutes: 60, Recurrence: "HOURLY"},
+			shouldErr: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.schedule.validate()
+			if tc.shouldErr && err == nil {
+				t.Fatalf("expected error, got none")
+			}
+			if !tc.shouldErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"fmt"
+)
+
+// OptionError is an error in the options passed to a call, e.g. an
+// unsupported option or an option value of the wrong type.
+type OptionError struct {
+	Option  string
+	Message string
+}
+
+// Error returns the option and the description of OptionError.
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("option %s: %s", e.Option, e.Message)
+}
+
+// unsupportedOption returns OptionError for the option not supported
+// by a call.
+func unsupportedOption(k string) error {
+	return &OptionError{Option: k, Message: "unsupported option"}
+}
+
+// stringOption returns the value of the option, when it is a string.
+func stringOption(k string, v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", &OptionError{Option: k, Message: fmt.Sprintf("expected string, found %T", v)}
+	}
+	return s, nil
+}
@@ -1,7 +1,7 @@
 package vrop
 
 import (
-	"fmt"
+	"encoding/json"
 )
 
 // PageInfo is paging reference.
@@ -16,33 +16,45 @@ type PageInfo struct {
 	SortBy string `json:"sortBy,omitempty"`
 	// A CSV list of values. If not specified or if list shorter than sortFields then SortOrder.ASCENDING is assumed.
 	SortOrder string `json:"sortOrder,omitempty"`
+	// Extra holds the values of the keys not supported by this package,
+	// when decoded in lenient mode.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
-func unpackPageInfo(m interface{}) (*PageInfo, error) {
-	var pim map[string]interface{}
-
-	switch m.(type) {
-	case map[string]interface{}:
-		pim = m.(map[string]interface{})
-	default:
-		return nil, fmt.Errorf("map not found")
+func unpackPageInfo(m interface{}, path string, d *decoder) (*PageInfo, error) {
+	pim, err := asObject(m, path)
+	if err != nil {
+		return nil, err
 	}
 
 	p := &PageInfo{}
 	for k, v := range pim {
 		switch k {
-		case "page":
-			p.ID = int(v.(float64))
-		case "pageSize":
-			p.Size = int(v.(float64))
-		case "totalCount":
-			p.Total = int(v.(float64))
+		case "page", "pageSize", "totalCount":
+			n, err := asNumber(v, joinPath(path, k))
+			if err != nil {
+				return nil, err
+			}
+			switch k {
+			case "page":
+				p.ID = int(n)
+			case "pageSize":
+				p.Size = int(n)
+			default:
+				p.Total = int(n)
+			}
 		case "sortBy":
-			p.SortBy = v.(string)
+			if p.SortBy, err = asString(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		case "sortOrder":
-			p.SortOrder = v.(string)
+			if p.SortOrder, err = asString(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		default:
-			return nil, fmt.Errorf("map contains unsupported key: %s", k)
+			if err := d.unknownKey(path, k, v, &p.Extra); err != nil {
+				return nil, err
+			}
 		}
 	}
 
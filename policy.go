@@ -0,0 +1,207 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strconv"
+)
+
+// Policy is a summary of a policy, i.e. the set of rules used to analyze
+// and display information about resources.
+type Policy struct {
+	// Identifier of the Policy.
+	ID string `json:"id,omitempty"`
+	// Name and description of the Policy.
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// The time the Policy was created and last modified.
+	CreationTime     Timestamp `json:"creationTime"`
+	LastModifiedTime Timestamp `json:"lastModifiedTime"`
+	// Whether the Policy is the default policy.
+	IsDefault bool `json:"defaultPolicy,omitempty"`
+	// Set of useful links related to the current object.
+	Links []*Link `json:"links,omitempty"`
+}
+
+// PoliciesResponse is a response with policies.
+type PoliciesResponse struct {
+	Page     *PageInfo `json:"pageInfo,omitempty"`
+	Links    []*Link   `json:"links,omitempty"`
+	Policies []*Policy `json:"policySummaries,omitempty"`
+}
+
+// PolicyAssignment is the policy applied to a custom group.
+type PolicyAssignment struct {
+	GroupID    string `json:"group_id,omitempty"`
+	GroupName  string `json:"group_name,omitempty"`
+	PolicyID   string `json:"policy_id,omitempty"`
+	PolicyName string `json:"policy_name,omitempty"`
+}
+
+// GetPolicies returns a list of policies.
+func (c *Client) GetPolicies() ([]*Policy, error) {
+	policies := []*Policy{}
+	if err := c.authenticate(); err != nil {
+		return policies, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := make(map[string]string)
+		params["page"] = strconv.Itoa(pageOffset)
+		params["pageSize"] = strconv.Itoa(pageSize)
+		b, err := c.request("GET", "policies", params)
+		if err != nil {
+			return policies, err
+		}
+
+		resp := &PoliciesResponse{}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return policies, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+
+		policies = append(policies, resp.Policies...)
+
+		if len(resp.Policies) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return policies, nil
+}
+
+// ExportPolicy writes the archive with the policy having the provided
+// identifier to the writer. The transfer fails when the context is done.
+func (c *Client) ExportPolicy(ctx context.Context, id string, w io.Writer) error {
+	if id == "" {
+		return fmt.Errorf("empty policy id")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := map[string]string{
+		"id": id,
+	}
+	if _, err := c.requestStream(ctx, "GET", "policies/export", params, nil, "", w); err != nil {
+		return fmt.Errorf("failed to export policy %s: %s", id, err)
+	}
+	return nil
+}
+
+// ImportPolicy imports the policy archive read from the reader, i.e. the
+// output of ExportPolicy. The existing policies with the same name are
+// overwritten only when overwrite is true; otherwise, the server refuses
+// the import. The archive is streamed to the server without buffering it
+// in memory. The transfer fails when the context is done.
+func (c *Client) ImportPolicy(ctx context.Context, r io.Reader, overwrite bool) error {
+	if r == nil {
+		return fmt.Errorf("nil policy reader")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("policy", "policy.zip")
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	params := map[string]string{
+		"forceImport": strconv.FormatBool(overwrite),
+	}
+	if _, err := c.requestStream(ctx, "POST", "policies/import", params, pr, mw.FormDataContentType(), nil); err != nil {
+		return fmt.Errorf("failed to import policy: %s", err)
+	}
+	return nil
+}
+
+// GetPolicyAssignments returns the policies applied to custom groups.
+func (c *Client) GetPolicyAssignments() ([]*PolicyAssignment, error) {
+	assignments := []*PolicyAssignment{}
+	policies, err := c.GetPolicies()
+	if err != nil {
+		return assignments, err
+	}
+	policyNames := make(map[string]string)
+	for _, p := range policies {
+		policyNames[p.ID] = p.Name
+	}
+	groups, err := c.GetCustomGroups()
+	if err != nil {
+		return assignments, err
+	}
+	for _, g := range groups {
+		if g.PolicyID == "" {
+			continue
+		}
+		a := &PolicyAssignment{
+			GroupID:    g.ID,
+			PolicyID:   g.PolicyID,
+			PolicyName: policyNames[g.PolicyID],
+		}
+		if g.Key != nil {
+			a.GroupName = g.Key.Name
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, nil
+}
+
+// AssignPolicy applies the policy with the provided identifier to the
+// custom groups with the provided identifiers.
+func (c *Client) AssignPolicy(policyID string, groupIDs []string) error {
+	if policyID == "" {
+		return fmt.Errorf("empty policy id")
+	}
+	if len(groupIDs) == 0 {
+		return fmt.Errorf("no custom group ids to assign policy %s", policyID)
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	payload := map[string]interface{}{
+		"id":       policyID,
+		"groupIds": groupIDs,
+	}
+	if _, err := c.requestWithPayload("POST", "policies/apply", params, payload); err != nil {
+		return fmt.Errorf("failed to assign policy %s: %s", policyID, err)
+	}
+	return nil
+}
+
+// ToJSONString serializes Policy to a string.
+func (p *Policy) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	. "github.com/greenpau/go-vrop/internal/server"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestPolicyTransfer(t *testing.T) {
+	archive, err := ioutil.ReadFile("testdata/responses/policy.zip")
+	if err != nil {
+		t.Fatalf("failed reading test data: %s", err)
+	}
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/policies/export": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "policy.zip"},
+		},
+		"/suite-api/api/policies/import": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "POST"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	ctx := context.Background()
+	buf := &bytes.Buffer{}
+	if err := cli.ExportPolicy(ctx, "policy-1", buf); err != nil {
+		t.Fatalf("failed exporting policy: %s", err)
+	}
+	if !bytes.Equal(buf.Bytes(), archive) {
+		t.Fatalf("unexpected policy archive: %q", buf.Bytes())
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("exported policy is not a zip archive: %s", err)
+	}
+	if len(zr.File) != 1 || zr.File[0].Name != "exportedPolicies.xml" {
+		t.Fatalf("unexpected policy archive content: %v", zr.File)
+	}
+
+	// The existing policy is overwritten only when asked for.
+	for _, overwrite := range []bool{false, true} {
+		if err := cli.ImportPolicy(ctx, bytes.NewReader(archive), overwrite); err != nil {
+			t.Fatalf("failed importing policy: %s", err)
+		}
+		requests := server.Requests()
+		req := requests[len(requests)-1]
+		want := fmt.Sprintf("/suite-api/api/policies/import?forceImport=%t", overwrite)
+		if req.Method != "POST" || req.RequestURI != want {
+			t.Fatalf("unexpected request: %s %s, want POST %s", req.Method, req.RequestURI, want)
+		}
+		if !bytes.Contains(req.Body, archive) || !bytes.Contains(req.Body, []byte(`name="policy"`)) {
+			t.Fatalf("policy archive not found in request body: %q", req.Body)
+		}
+	}
+}
+
+func TestPolicyTransferCanceled(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/policies/export": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "policy.zip", Delay: 5 * time.Second},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	// The transfer from the stalled server fails when the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := cli.ExportPolicy(ctx, "policy-1", ioutil.Discard); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Fatalf("policy export was not canceled: took %s", time.Since(start))
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// Recommendation is a recommendation for resolving an alert.
+type Recommendation struct {
+	// Identifier of the Recommendation.
+	ID string `json:"id,omitempty"`
+	// The text of the Recommendation.
+	Description string `json:"description,omitempty"`
+	// The action that implements the Recommendation, if any.
+	Action *RecommendationAction `json:"action,omitempty"`
+	// The priority of the Recommendation in an alert definition. The lower
+	// the number, the higher the priority.
+	Priority int `json:"priority,omitempty"`
+	// Set of useful links related to the current object.
+	Links []*Link `json:"links,omitempty"`
+}
+
+// RecommendationAction is an action that implements a recommendation.
+type RecommendationAction struct {
+	AdapterKindKey string `json:"adapterKindKey,omitempty"`
+	ActionID       string `json:"actionId,omitempty"`
+}
+
+// RecommendationsResponse is a response with recommendations.
+type RecommendationsResponse struct {
+	Page            *PageInfo         `json:"pageInfo,omitempty"`
+	Links           []*Link           `json:"links,omitempty"`
+	Recommendations []*Recommendation `json:"recommendations,omitempty"`
+}
+
+// AlertDetail is an alert with its active symptoms and recommendations.
+type AlertDetail struct {
+	*Alert
+	Symptoms        []*Symptom        `json:"symptoms,omitempty"`
+	Recommendations []*Recommendation `json:"recommendations,omitempty"`
+}
+
+// GetRecommendation returns the recommendation with the provided identifier.
+func (c *Client) GetRecommendation(id string) (*Recommendation, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty recommendation id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.request("GET", "recommendations/"+id, params)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recommendation{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return r, nil
+}
+
+// GetRecommendations returns the recommendations for the alert with the
+// provided identifier, ordered by priority.
+func (c *Client) GetRecommendations(alertID string) ([]*Recommendation, error) {
+	alert, err := c.GetAlert(alertID)
+	if err != nil {
+		return nil, err
+	}
+	definition, err := c.GetAlertDefinition(alert.DefinitionID)
+	if err != nil {
+		return nil, err
+	}
+	return c.getAlertDefinitionRecommendations(definition)
+}
+
+// GetAlertDetail returns the alert with the provided identifier together
+// with the active symptoms that triggered it and its recommendations.
+func (c *Client) GetAlertDetail(id string) (*AlertDetail, error) {
+	alert, err := c.GetAlert(id)
+	if err != nil {
+		return nil, err
+	}
+	detail := &AlertDetail{Alert: alert}
+	definition, err := c.GetAlertDefinition(alert.DefinitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	symptomIDs := make(map[string]bool)
+	for _, state := range definition.States {
+		collectSymptomDefinitionIDs(state.SymptomSet, symptomIDs)
+	}
+	symptoms, err := c.GetSymptoms([]string{alert.ResourceID}, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, symptom := range symptoms {
+		if symptomIDs[symptom.DefinitionID] {
+			detail.Symptoms = append(detail.Symptoms, symptom)
+		}
+	}
+
+	recommendations, err := c.getAlertDefinitionRecommendations(definition)
+	if err != nil {
+		return nil, err
+	}
+	detail.Recommendations = recommendations
+	return detail, nil
+}
+
+func (c *Client) getAlertDefinitionRecommendations(d *AlertDefinition) ([]*Recommendation, error) {
+	recommendations := []*Recommendation{}
+	priorities := make(map[string]int)
+	for _, state := range d.States {
+		for id, priority := range state.RecommendationPriorities {
+			if p, exists := priorities[id]; !exists || priority < p {
+				priorities[id] = priority
+			}
+		}
+	}
+	ids := []string{}
+	for id := range priorities {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	items, err := c.getRecommendationsByID(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range items {
+		priority, exists := priorities[r.ID]
+		if !exists {
+			continue
+		}
+		r.Priority = priority
+		recommendations = append(recommendations, r)
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Priority != recommendations[j].Priority {
+			return recommendations[i].Priority < recommendations[j].Priority
+		}
+		return recommendations[i].ID < recommendations[j].ID
+	})
+	return recommendations, nil
+}
+
+// getRecommendationsByID returns the recommendations with the provided
+// identifiers, queried in batches rather than one at a time.
+func (c *Client) getRecommendationsByID(ids []string) ([]*Recommendation, error) {
+	recommendations := []*Recommendation{}
+	if len(ids) == 0 {
+		return recommendations, nil
+	}
+	if err := c.authenticate(); err != nil {
+		return recommendations, err
+	}
+	pageSize := 100
+	for i := 0; i < len(ids); i += pageSize {
+		j := i + pageSize
+		if j > len(ids) {
+			j = len(ids)
+		}
+		params := url.Values{}
+		for _, id := range ids[i:j] {
+			params.Add("id", id)
+		}
+		params.Set("page", "0")
+		params.Set("pageSize", strconv.Itoa(pageSize))
+		b, err := c.requestWithValues("GET", "recommendations", params, nil)
+		if err != nil {
+			return recommendations, err
+		}
+		resp := &RecommendationsResponse{}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return recommendations, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+		recommendations = append(recommendations, resp.Recommendations...)
+	}
+	return recommendations, nil
+}
+
+func collectSymptomDefinitionIDs(set *SymptomSet, ids map[string]bool) {
+	if set == nil {
+		return
+	}
+	for _, id := range set.SymptomDefinitionIDs {
+		ids[id] = true
+	}
+	for _, nested := range set.SymptomSets {
+		collectSymptomDefinitionIDs(nested, ids)
+	}
+}
+
+// ToJSONString serializes AlertDetail to a string.
+func (d *AlertDetail) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(d)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	. "github.com/greenpau/go-vrop/internal/server"
+	"net/url"
+	"testing"
+)
+
+func newAlertDetailMockClient(t *testing.T) (*Client, *MockTestServer) {
+	return newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/alerts/alert-1": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "alert.json"},
+		},
+		"/suite-api/api/alertdefinitions/AlertDefinition-e5f6a7b8-0001": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "alert_definition.json"},
+		},
+		"/suite-api/api/symptoms": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "symptoms.json"},
+		},
+		"/suite-api/api/recommendations": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "recommendations.json"},
+		},
+	})
+}
+
+// checkRecommendations checks the recommendations of the alert are
+// ordered by priority, and were fetched in a single request.
+func checkRecommendations(t *testing.T, server *MockTestServer, recommendations []*Recommendation) {
+	if len(recommendations) != 2 {
+		t.Fatalf("expected 2 recommendations, got %d", len(recommendations))
+	}
+	for i, id := range []string{"Recommendation-df-VMWARE-1", "Recommendation-df-VMWARE-2"} {
+		r := recommendations[i]
+		if r.ID != id || r.Priority != i+1 {
+			t.Fatalf("unexpected recommendation %d: %+v", i, r)
+		}
+	}
+	if a := recommendations[0].Action; a == nil || a.ActionID != "Action-VMWARE-ExtendDisk" {
+		t.Fatalf("unexpected recommendation action: %+v", a)
+	}
+
+	var found []*url.URL
+	for _, req := range server.Requests() {
+		u, err := url.Parse(req.RequestURI)
+		if err != nil {
+			t.Fatalf("failed parsing request uri: %s", err)
+		}
+		if u.Path == "/suite-api/api/recommendations" {
+			found = append(found, u)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected 1 recommendations request, got %d", len(found))
+	}
+	ids := found[0].Query()["id"]
+	if len(ids) != 2 || ids[0] != "Recommendation-df-VMWARE-1" || ids[1] != "Recommendation-df-VMWARE-2" {
+		t.Fatalf("unexpected id params: %v", ids)
+	}
+}
+
+func TestGetRecommendations(t *testing.T) {
+	cli, server := newAlertDetailMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	recommendations, err := cli.GetRecommendations("alert-1")
+	if err != nil {
+		t.Fatalf("failed getting recommendations: %s", err)
+	}
+	checkRecommendations(t, server, recommendations)
+}
+
+func TestGetAlertDetail(t *testing.T) {
+	cli, server := newAlertDetailMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	detail, err := cli.GetAlertDetail("alert-1")
+	if err != nil {
+		t.Fatalf("failed getting alert detail: %s", err)
+	}
+	if detail.ID != "alert-1" || detail.ResourceID != "vm-1" {
+		t.Fatalf("unexpected alert: %+v", detail.Alert)
+	}
+	// Only the symptoms of the alert definition are included.
+	if len(detail.Symptoms) != 1 || detail.Symptoms[0].ID != "symptom-1" {
+		t.Fatalf("unexpected symptoms: %+v", detail.Symptoms)
+	}
+	checkRecommendations(t, server, detail.Recommendations)
+
+	if _, err := cli.GetAlertDetail("alert-2"); err == nil {
+		t.Fatalf("expected error for unknown alert, got none")
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// The types of relationships between resources.
+const (
+	// RelationshipParent selects the immediate parents of a resource.
+	RelationshipParent = "PARENT"
+	// RelationshipChild selects the immediate children of a resource.
+	RelationshipChild = "CHILD"
+	// RelationshipAll selects both parents and children of a resource.
+	RelationshipAll = "ALL"
+)
+
+// RelationshipsResponse is a response with the resources related
+// to a particular resource.
+type RelationshipsResponse struct {
+	Page             *PageInfo   `json:"pageInfo,omitempty"`
+	Links            []*Link     `json:"links,omitempty"`
+	Resources        []*Resource `json:"resourceList,omitempty"`
+	RelationshipType string      `json:"relationshipType,omitempty"`
+	// Lenient instructs the unpacking to preserve the keys not supported
+	// by this package in Extra fields, rather than fail.
+	Lenient bool `json:"-"`
+	// Warnings are the keys not supported by this package, found when
+	// unpacked in lenient mode.
+	Warnings []string `json:"-"`
+	// Extra holds the values of the keys not supported by this package,
+	// when unpacked in lenient mode.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// GetRelationships returns the resources related to the resource with
+// the provided identifier. The relationship type is one of PARENT, CHILD,
+// or ALL. When the type is empty, all relationships are returned.
+func (c *Client) GetRelationships(id, relationshipType string) ([]*Resource, error) {
+	resources := []*Resource{}
+	if id == "" {
+		return resources, fmt.Errorf("empty resource id")
+	}
+	switch relationshipType {
+	case "":
+		relationshipType = RelationshipAll
+	case RelationshipParent, RelationshipChild, RelationshipAll:
+	default:
+		return resources, fmt.Errorf("unsupported relationship type: %s", relationshipType)
+	}
+
+	if err := c.authenticate(); err != nil {
+		return resources, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := make(map[string]string)
+		params["relationshipType"] = relationshipType
+		params["page"] = strconv.Itoa(pageOffset)
+		params["pageSize"] = strconv.Itoa(pageSize)
+		b, err := c.request("GET", "resources/"+id+"/relationships", params)
+		if err != nil {
+			return resources, err
+		}
+
+		resp := &RelationshipsResponse{Lenient: c.lenientDecoding}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return resources, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+		c.logWarnings(resp.Warnings)
+
+		resources = append(resources, resp.Resources...)
+
+		if len(resp.Resources) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return resources, nil
+}
+
+// UnmarshalJSON unpacks byte array into RelationshipsResponse.
+func (c *RelationshipsResponse) UnmarshalJSON(b []byte) error {
+	obj := "RelationshipsResponse"
+	var requiredKeys = map[string]bool{
+		"resourceList": false,
+		"pageInfo":     false,
+	}
+	var optionalKeys = map[string]bool{
+		"links":            false,
+		"relationshipType": false,
+	}
+	var m map[string]interface{}
+	if len(b) < 10 {
+		return fmt.Errorf("invalid %s data: %s", obj, b)
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", obj, err)
+	}
+
+	d := &decoder{lenient: c.Lenient}
+	for k, v := range m {
+		if _, exists := requiredKeys[k]; exists {
+			requiredKeys[k] = true
+			continue
+		}
+		if _, exists := optionalKeys[k]; exists {
+			optionalKeys[k] = true
+			continue
+		}
+		if d.lenient {
+			if err := d.unknownKey("", k, v, &c.Extra); err != nil {
+				return fmt.Errorf("failed to unpack %s: %w", obj, err)
+			}
+			continue
+		}
+		return fmt.Errorf("failed to unpack %s, found unsupported key: %s", obj, k)
+	}
+
+	for k, present := range requiredKeys {
+		if !present {
+			return fmt.Errorf("failed to unpack %s, required key not found: %s", obj, k)
+		}
+	}
+
+	p, err := unpackPageInfo(m["pageInfo"], "pageInfo", d)
+	if err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", obj, err)
+	}
+	c.Page = p
+
+	if optionalKeys["relationshipType"] {
+		c.RelationshipType, err = asString(m["relationshipType"], "relationshipType")
+		if err != nil {
+			return fmt.Errorf("failed to unpack %s: %w", obj, err)
+		}
+	}
+
+	if optionalKeys["links"] {
+		c.Links, err = unpackLinks(m["links"], "links", d)
+		if err != nil {
+			return fmt.Errorf("failed to unpack %s: %w", obj, err)
+		}
+	}
+
+	c.Resources, err = unpackResources(m["resourceList"], "resourceList", d)
+	if err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", obj, err)
+	}
+
+	c.Warnings = d.Warnings()
+	return nil
+}
@@ -0,0 +1,255 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The statuses of reports.
+const (
+	ReportStatusQueued    = "QUEUED"
+	ReportStatusRunning   = "RUNNING"
+	ReportStatusCompleted = "COMPLETED"
+	ReportStatusFailed    = "FAILED"
+)
+
+// The formats of downloaded reports.
+const (
+	ReportFormatPDF = "PDF"
+	ReportFormatCSV = "CSV"
+)
+
+// ReportDefinition is the definition of a report.
+type ReportDefinition struct {
+	// Identifier of the ReportDefinition.
+	ID string `json:"id,omitempty"`
+	// Name and description of the ReportDefinition.
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// The user owning the ReportDefinition.
+	Owner string `json:"owner,omitempty"`
+	// Whether the ReportDefinition is active.
+	Active bool `json:"active,omitempty"`
+	// The resource kinds the report could be generated for.
+	Subject []string `json:"subject,omitempty"`
+	// Set of useful links related to the current object.
+	Links []*Link `json:"links,omitempty"`
+}
+
+// Report is a report generated for a resource.
+type Report struct {
+	// Identifier of the Report.
+	ID string `json:"id,omitempty"`
+	// Name of the Report.
+	Name string `json:"name,omitempty"`
+	// Identifier of the Resource the Report was generated for.
+	ResourceID string `json:"resourceId,omitempty"`
+	// Identifier of the definition of the Report.
+	DefinitionID string `json:"reportDefinitionId,omitempty"`
+	// The status of the Report, i.e. QUEUED, RUNNING, COMPLETED, FAILED.
+	Status string `json:"status,omitempty"`
+	// The time the Report was completed, as reported by the server.
+	CompletionTime string `json:"completionTime,omitempty"`
+	// The user generating the Report.
+	Owner string `json:"owner,omitempty"`
+	// Set of useful links related to the current object.
+	Links []*Link `json:"links,omitempty"`
+}
+
+// ReportDefinitionsResponse is a response with report definitions.
+type ReportDefinitionsResponse struct {
+	Page              *PageInfo           `json:"pageInfo,omitempty"`
+	Links             []*Link             `json:"links,omitempty"`
+	ReportDefinitions []*ReportDefinition `json:"reportDefinitions,omitempty"`
+}
+
+// GetReportDefinitions returns a list of report definitions.
+func (c *Client) GetReportDefinitions() ([]*ReportDefinition, error) {
+	definitions := []*ReportDefinition{}
+	if err := c.authenticate(); err != nil {
+		return definitions, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := make(map[string]string)
+		params["page"] = strconv.Itoa(pageOffset)
+		params["pageSize"] = strconv.Itoa(pageSize)
+		b, err := c.request("GET", "reportdefinitions", params)
+		if err != nil {
+			return definitions, err
+		}
+
+		resp := &ReportDefinitionsResponse{}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return definitions, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+
+		definitions = append(definitions, resp.ReportDefinitions...)
+
+		if len(resp.ReportDefinitions) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return definitions, nil
+}
+
+// GenerateReport starts the generation of the report with the provided
+// definition for the resource with the provided identifier.
+func (c *Client) GenerateReport(definitionID, resourceID string) (*Report, error) {
+	if definitionID == "" {
+		return nil, fmt.Errorf("empty report definition id")
+	}
+	if resourceID == "" {
+		return nil, fmt.Errorf("empty resource id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	payload := map[string]string{
+		"reportDefinitionId": definitionID,
+		"resourceId":         resourceID,
+	}
+	b, err := c.requestWithPayload("POST", "reports", params, payload)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return r, nil
+}
+
+// GetReport returns the report with the provided identifier.
+func (c *Client) GetReport(id string) (*Report, error) {
+	return c.getReport(context.Background(), id)
+}
+
+func (c *Client) getReport(ctx context.Context, id string) (*Report, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty report id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	b, err := c.requestWithContext(ctx, "GET", "reports/"+id, url.Values{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return r, nil
+}
+
+// WaitForReport polls the status of the report with the provided
+// identifier until the report is completed. It returns an error when the
+// report fails or the context is done before the report is completed,
+// including during a check of the status. The interval between the checks
+// is set with SetReportPollInterval.
+func (c *Client) WaitForReport(ctx context.Context, id string) (*Report, error) {
+	ticker := time.NewTicker(c.reportPollInterval)
+	defer ticker.Stop()
+	var last *Report
+	for {
+		r, err := c.getReport(ctx, id)
+		if err != nil {
+			if last != nil && ctx.Err() != nil {
+				return last, fmt.Errorf("report %s is %s: %s", id, last.Status, ctx.Err())
+			}
+			return nil, fmt.Errorf("failed to check report %s: %s", id, err)
+		}
+		last = r
+		switch r.Status {
+		case ReportStatusCompleted:
+			return r, nil
+		case ReportStatusFailed:
+			return r, fmt.Errorf("report %s failed", id)
+		}
+		select {
+		case <-ctx.Done():
+			return r, fmt.Errorf("report %s is %s: %s", id, r.Status, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// DownloadReport writes the report with the provided identifier in the
+// provided format, i.e. PDF or CSV, to the writer. The report is streamed
+// to the writer without buffering it in memory. The download fails when
+// the context is done.
+func (c *Client) DownloadReport(ctx context.Context, id, format string, w io.Writer) error {
+	if id == "" {
+		return fmt.Errorf("empty report id")
+	}
+	format = strings.ToUpper(format)
+	switch format {
+	case ReportFormatPDF, ReportFormatCSV:
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+	if w == nil {
+		return fmt.Errorf("nil report writer")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := map[string]string{
+		"format": format,
+	}
+	if _, err := c.requestStream(ctx, "GET", "reports/"+id+"/download", params, nil, "", w); err != nil {
+		return fmt.Errorf("failed to download report %s: %s", id, err)
+	}
+	return nil
+}
+
+// DeleteReport deletes the report with the provided identifier.
+func (c *Client) DeleteReport(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty report id")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	if _, err := c.request("DELETE", "reports/"+id, params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ToJSONString serializes Report to a string.
+func (r *Report) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	. "github.com/greenpau/go-vrop/internal/server"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newReportMockClient(t *testing.T) (*Client, *MockTestServer) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/reports": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "POST", FileName: "report_queued.json"},
+		},
+		"/suite-api/api/reports/report-2": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "report_completed.json"},
+		},
+		"/suite-api/api/reports/report-3": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "report_failed.json"},
+		},
+		"/suite-api/api/reports/report-4": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "report_running.json"},
+		},
+		"/suite-api/api/reports/report-5": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "report_running.json", Delay: 5 * time.Second},
+		},
+		"/suite-api/api/reports/report-2/download": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "report.csv"},
+		},
+		"/suite-api/api/reports/report-5/download": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "report.csv", Delay: 5 * time.Second},
+		},
+	})
+	if err := cli.SetReportPollInterval(10 * time.Millisecond); err != nil {
+		t.Fatalf("failed setting report poll interval: %s", err)
+	}
+	return cli, server
+}
+
+func TestGenerateReport(t *testing.T) {
+	cli, server := newReportMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	r, err := cli.GenerateReport("def-1", "vm-1")
+	if err != nil {
+		t.Fatalf("failed generating report: %s", err)
+	}
+	if r.ID != "report-1" || r.Status != ReportStatusQueued {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+
+	requests := server.Requests()
+	req := requests[len(requests)-1]
+	if req.Method != "POST" || !strings.HasPrefix(req.RequestURI, "/suite-api/api/reports?") {
+		t.Fatalf("unexpected request: %s %s", req.Method, req.RequestURI)
+	}
+	payload := map[string]string{}
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		t.Fatalf("failed unpacking request payload: %s", err)
+	}
+	want := map[string]string{"reportDefinitionId": "def-1", "resourceId": "vm-1"}
+	if !reflect.DeepEqual(payload, want) {
+		t.Fatalf("unexpected payload: %s", req.Body)
+	}
+
+	if _, err := cli.GenerateReport("", "vm-1"); err == nil {
+		t.Fatalf("expected error on empty report definition id")
+	}
+}
+
+func TestWaitForReport(t *testing.T) {
+	testcases := []struct {
+		name    string
+		id      string
+		timeout time.Duration
+		status  string
+		err     string
+	}{
+		{
+			name:    "completed",
+			id:      "report-2",
+			timeout: 2 * time.Second,
+			status:  ReportStatusCompleted,
+		},
+		{
+			name:    "failed",
+			id:      "report-3",
+			timeout: 2 * time.Second,
+			status:  ReportStatusFailed,
+			err:     "report report-3 failed",
+		},
+		{
+			name:    "still running at deadline",
+			id:      "report-4",
+			timeout: 100 * time.Millisecond,
+			status:  ReportStatusRunning,
+			err:     "report report-4 is RUNNING: context deadline exceeded",
+		},
+		{
+			name:    "status check stalled at deadline",
+			id:      "report-5",
+			timeout: 100 * time.Millisecond,
+			err:     "failed to check report report-5",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli, server := newReportMockClient(t)
+			defer server.Close()
+			defer cli.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+			start := time.Now()
+			r, err := cli.WaitForReport(ctx, tc.id)
+			if elapsed := time.Since(start); elapsed > tc.timeout+time.Second {
+				t.Fatalf("waiting for report outlived the deadline: took %s", elapsed)
+			}
+			if tc.err == "" && err != nil {
+				t.Fatalf("failed waiting for report: %s", err)
+			}
+			if tc.err != "" && (err == nil || !strings.HasPrefix(err.Error(), tc.err)) {
+				t.Fatalf("expected error %q, got: %v", tc.err, err)
+			}
+			if tc.status != "" && (r == nil || r.Status != tc.status) {
+				t.Fatalf("expected report status %s, got: %+v", tc.status, r)
+			}
+		})
+	}
+}
+
+func TestDownloadReport(t *testing.T) {
+	cli, server := newReportMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	content, err := ioutil.ReadFile("testdata/responses/report.csv")
+	if err != nil {
+		t.Fatalf("failed reading test data: %s", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := cli.DownloadReport(context.Background(), "report-2", "csv", buf); err != nil {
+		t.Fatalf("failed downloading report: %s", err)
+	}
+	if !bytes.Equal(buf.Bytes(), content) {
+		t.Fatalf("unexpected report content: %q", buf.Bytes())
+	}
+	requests := server.Requests()
+	if req := requests[len(requests)-1]; req.RequestURI != "/suite-api/api/reports/report-2/download?format=CSV" {
+		t.Fatalf("unexpected request: %s", req.RequestURI)
+	}
+
+	if err := cli.DownloadReport(context.Background(), "report-2", "xml", buf); err == nil {
+		t.Fatalf("expected error on unsupported format")
+	}
+}
+
+func TestDownloadReportCanceled(t *testing.T) {
+	cli, server := newReportMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	// The download from the stalled server fails when the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := cli.DownloadReport(ctx, "report-5", ReportFormatPDF, ioutil.Discard); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Fatalf("report download was not canceled: took %s", time.Since(start))
+	}
+}
+
+func TestSetReportPollInterval(t *testing.T) {
+	cli, err := NewClient(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("failed initializing client: %s", err)
+	}
+	if err := cli.SetReportPollInterval(0); err == nil {
+		t.Fatalf("expected error on zero interval")
+	}
+}
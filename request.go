@@ -15,9 +15,13 @@
 package vrop
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"go.uber.org/zap"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -27,45 +31,62 @@ import (
 )
 
 func (c *Client) request(method, svc string, params map[string]string) ([]byte, error) {
+	return c.requestWithPayload(method, svc, params, nil)
+}
+
+// requestWithPayload makes an API call with the payload serialized
+// to JSON in the body of the request, unless the payload is nil.
+func (c *Client) requestWithPayload(method, svc string, params map[string]string, payload interface{}) ([]byte, error) {
+	q := url.Values{}
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	return c.requestWithValues(method, svc, q, payload)
+}
+
+// requestWithValues makes an API call with the query parameters having
+// multiple values, e.g. resourceId=1&resourceId=2.
+func (c *Client) requestWithValues(method, svc string, q url.Values, payload interface{}) ([]byte, error) {
+	return c.requestWithContext(context.Background(), method, svc, q, payload)
+}
+
+// requestWithContext makes an API call bound by the context, in addition
+// to the timeout of the client, e.g. to honor the deadline of a caller
+// polling for a status.
+func (c *Client) requestWithContext(ctx context.Context, method, svc string, q url.Values, payload interface{}) ([]byte, error) {
 	reqURL := fmt.Sprintf("%s%s%s", c.url, c.pathPrefix, svc)
 	c.log.Debug(
 		"making http request",
 		zap.String("method", method),
 		zap.String("url", reqURL),
-		zap.Any("params", params),
+		zap.Any("params", q),
 	)
 
-	q := url.Values{}
-	for k, v := range params {
-		q.Set(k, v)
-	}
-
 	reqURL = fmt.Sprintf("%s?%s", reqURL, q.Encode())
 
-	tr := &http.Transport{
-		Dial: (&net.Dialer{
-			Timeout: 10 * time.Second,
-		}).Dial,
-		TLSHandshakeTimeout: 10 * time.Second,
-	}
-	if !c.validateServerCert {
-		tr.TLSClientConfig = &tls.Config{
-			InsecureSkipVerify: true,
+	httpClient := c.newHTTPClient(time.Second * 30)
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed marshalling request payload: %s", err)
 		}
-	}
-	httpClient := &http.Client{
-		Transport: tr,
-		Timeout:   time.Second * 30,
+		body = bytes.NewBuffer(data)
 	}
 
 	var req *http.Request
 	var err error
-	req, err = http.NewRequest(method, reqURL, nil)
+	req, err = http.NewRequest(method, reqURL, body)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
-	req.Header.Add("Authorization", fmt.Sprintf("vRealizeOpsToken %s", c.token))
+	if payload != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("vRealizeOpsToken %s", c.authToken()))
 	req.Header.Add("Accept", "application/json;charset=utf-8")
 	req.Header.Add("Cache-Control", "no-cache")
 
@@ -82,19 +103,145 @@ func (c *Client) request(method, svc string, params map[string]string) ([]byte,
 
 	c.log.Debug("http response", zap.String("status", res.Status))
 
-	body, err := ioutil.ReadAll(res.Body)
+	respBody, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return nil, fmt.Errorf("non-EOF error at url %s: %s", reqURL, err)
 	}
 
-	// c.log.Debug("http response body", zap.String("body", string(body)))
+	// c.log.Debug("http response body", zap.String("body", string(respBody)))
+
+	switch res.StatusCode {
+	case 200, 201, 202, 204:
+		return respBody, nil
+	default:
+		return nil, fmt.Errorf("error: status code %d: %s", res.StatusCode, string(respBody))
+	}
+}
+
+// requestCasa makes an API call to the cluster administration (CASA) API.
+// Unlike the Suite API, it authenticates with the username and password of
+// the administrator, rather than with a token.
+func (c *Client) requestCasa(method, svc string) ([]byte, error) {
+	reqURL := fmt.Sprintf("%s%s%s", c.url, c.casaPathPrefix, svc)
+	c.log.Debug(
+		"making http request",
+		zap.String("method", method),
+		zap.String("url", reqURL),
+	)
+
+	httpClient := c.newHTTPClient(time.Second * 30)
+
+	req, err := http.NewRequest(method, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Add("Accept", "application/json;charset=utf-8")
+	req.Header.Add("Cache-Control", "no-cache")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	c.log.Debug("http response", zap.String("status", res.Status))
+
+	// Read one byte past the limit to tell the response exceeding the limit
+	// from the one being exactly at the limit.
+	respBody, err := ioutil.ReadAll(io.LimitReader(res.Body, c.dataLimit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed reading response at url %s: %s", reqURL, err)
+	}
+	if int64(len(respBody)) > c.dataLimit {
+		return nil, fmt.Errorf("response too large at url %s: exceeds %d bytes", reqURL, c.dataLimit)
+	}
 
 	switch res.StatusCode {
 	case 200:
-		return body, nil
+		return respBody, nil
 	default:
-		return nil, fmt.Errorf("error: status code %d: %s", res.StatusCode, string(body))
+		return nil, fmt.Errorf("error: status code %d: %s", res.StatusCode, string(respBody))
+	}
+}
+
+// requestStream makes an API call with the body of the request read from
+// the provided reader, if any, and copies the body of the response to the
+// provided writer, if any. The response is not buffered in memory, which
+// makes it suitable for binary payloads, e.g. zip archives or PDF files.
+// The transfer is not bound by a timeout, so that large payloads are not
+// cut short, and the context is the means to cancel it.
+func (c *Client) requestStream(ctx context.Context, method, svc string, params map[string]string, body io.Reader, contentType string, w io.Writer) (int64, error) {
+	reqURL := fmt.Sprintf("%s%s%s", c.url, c.pathPrefix, svc)
+	c.log.Debug(
+		"making http request",
+		zap.String("method", method),
+		zap.String("url", reqURL),
+		zap.Any("params", params),
+	)
+
+	q := url.Values{}
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	reqURL = fmt.Sprintf("%s?%s", reqURL, q.Encode())
+
+	httpClient := c.newHTTPClient(0)
+
+	req, err := http.NewRequest(method, reqURL, body)
+	if err != nil {
+		return 0, err
 	}
+	req = req.WithContext(ctx)
 
-	return body, nil
+	if contentType != "" {
+		req.Header.Add("Content-Type", contentType)
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("vRealizeOpsToken %s", c.authToken()))
+	req.Header.Add("Accept", "*/*")
+	req.Header.Add("Cache-Control", "no-cache")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	c.log.Debug("http response", zap.String("status", res.Status))
+
+	switch res.StatusCode {
+	case 200, 201, 202, 204:
+	default:
+		respBody, _ := ioutil.ReadAll(io.LimitReader(res.Body, c.dataLimit))
+		return 0, fmt.Errorf("error: status code %d: %s", res.StatusCode, string(respBody))
+	}
+
+	if w == nil {
+		w = ioutil.Discard
+	}
+	n, err := io.Copy(w, res.Body)
+	if err != nil {
+		return n, fmt.Errorf("failed reading response at url %s: %s", reqURL, err)
+	}
+	return n, nil
+}
+
+// newHTTPClient returns HTTP client with the provided timeout. When the
+// timeout is zero, the requests made by the client do not time out.
+func (c *Client) newHTTPClient(timeout time.Duration) *http.Client {
+	tr := &http.Transport{
+		Dial: (&net.Dialer{
+			Timeout: 10 * time.Second,
+		}).Dial,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	if !c.validateServerCert {
+		tr.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+	return &http.Client{
+		Transport: tr,
+		Timeout:   timeout,
+	}
 }
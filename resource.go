@@ -1,7 +1,10 @@
 package vrop
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -23,7 +26,7 @@ type Resource struct {
 	// as reported by one or more adapter instances
 	StatusStates []*ResourceStatusState `json:"resourceStatusStates,omitempty"`
 	// Health of the Resource.
-	Health string `json:"resourceHealth,omitempty"`
+	Health Color `json:"resourceHealth,omitempty"`
 	// Resource Health Score.
 	HealthValue float64 `json:"resourceHealthValue,omitempty"`
 	// DT calculation enabled or not. By default DT calculation for
@@ -34,88 +37,715 @@ type Resource struct {
 	// The various major and minor badges and their values for a Resource.
 	Badges []*Badge `json:"badges,omitempty"`
 	// Collection of related resource identifiers.
-	RelatedResources []interface{} `json:"relatedResources,omitempty"`
-	// Extension values that were added to the given object by third-party.
-	Extension interface{} `json:"extension,omitempty"`
+	RelatedResources []string `json:"relatedResources,omitempty"`
+	// Extension values that were added to the given object by third-party,
+	// preserved as raw JSON. See DecodeExtension.
+	Extension json.RawMessage `json:"extension,omitempty"`
 	// Set of useful links related to the current object.
 	Links []*Link `json:"links,omitempty"`
+	// Extra holds the values of the keys not supported by this package,
+	// when decoded in lenient mode.
+	Extra map[string]json.RawMessage `json:"-"`
+	// keys are the keys found in the response the Resource was unpacked
+	// from.
+	keys keySet
 }
 
-func unpackResource(m interface{}) (*Resource, error) {
-	var rmap map[string]interface{}
-
-	switch m.(type) {
-	case map[string]interface{}:
-		rmap = m.(map[string]interface{})
-	default:
-		return nil, fmt.Errorf("map not found")
+func unpackResource(m interface{}, path string, d *decoder) (*Resource, error) {
+	rmap, err := asObject(m, path)
+	if err != nil {
+		return nil, err
 	}
 
-	r := &Resource{}
+	r := &Resource{keys: newKeySet(rmap)}
 	for k, v := range rmap {
+		keyPath := joinPath(path, k)
 		switch k {
 		case "identifier":
-			r.ID = v.(string)
+			if r.ID, err = asString(v, keyPath); err != nil {
+				return nil, err
+			}
 		case "description":
-			r.Description = v.(string)
+			if r.Description, err = asString(v, keyPath); err != nil {
+				return nil, err
+			}
 		case "creationTime":
-			secs := int64(v.(float64) / 1000)
-			nsecs := int64(((v.(float64) / 1000) - float64(secs)) * 1e9)
-			r.CreationTime = time.Unix(secs, nsecs)
-		case "resourceKey":
-			s, err := unpackResourceKey(v.(interface{}))
+			n, err := asNumber(v, keyPath)
 			if err != nil {
-				return nil, fmt.Errorf("failed to unpack %s resourceKey: %s", k, err)
+				return nil, err
+			}
+			r.CreationTime = epochMillisToTime(n)
+		case "resourceKey":
+			if r.Key, err = unpackResourceKey(v, keyPath, d); err != nil {
+				return nil, err
 			}
-			r.Key = s
 		case "credentialInstanceId":
-			r.CredentialInstanceID = v.(string)
+			if r.CredentialInstanceID, err = asString(v, keyPath); err != nil {
+				return nil, err
+			}
 		case "geoLocation":
-			s, err := unpackGeoLocation(v.(interface{}))
-			if err != nil {
-				return nil, fmt.Errorf("failed to unpack %s geoLocation: %s", k, err)
+			if r.GeoLocation, err = unpackGeoLocation(v, keyPath, d); err != nil {
+				return nil, err
 			}
-			r.GeoLocation = s
 		case "resourceStatusStates":
-			for _, item := range v.([]interface{}) {
-				s, err := unpackResourceStatusState(item)
+			items, err := asArray(v, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			r.StatusStates = []*ResourceStatusState{}
+			for i, item := range items {
+				s, err := unpackResourceStatusState(item, indexPath(keyPath, i), d)
 				if err != nil {
-					return nil, fmt.Errorf("failed to unpack %s resourceStatusStates: %s", k, err)
+					return nil, err
 				}
 				r.StatusStates = append(r.StatusStates, s)
 			}
 		case "resourceHealth":
-			r.Health = v.(string)
+			s, err := d.enum(v, keyPath, func(s string) bool { return Color(s).Valid() })
+			if err != nil {
+				return nil, err
+			}
+			r.Health = Color(s)
 		case "resourceHealthValue":
-			r.HealthValue = v.(float64)
+			if r.HealthValue, err = asNumber(v, keyPath); err != nil {
+				return nil, err
+			}
 		case "dtEnabled":
-			r.DynamicThresholdEnabled = v.(bool)
+			if r.DynamicThresholdEnabled, err = asBool(v, keyPath); err != nil {
+				return nil, err
+			}
 		case "monitoringInterval":
-			r.MonitoringInterval = v.(float64)
+			if r.MonitoringInterval, err = asNumber(v, keyPath); err != nil {
+				return nil, err
+			}
 		case "badges":
-			for _, item := range v.([]interface{}) {
-				badge, err := unpackBadge(item)
+			items, err := asArray(v, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			r.Badges = []*Badge{}
+			for i, item := range items {
+				badge, err := unpackBadge(item, indexPath(keyPath, i), d)
 				if err != nil {
-					return nil, fmt.Errorf("failed to unpack %s badge: %s", k, err)
+					return nil, err
 				}
 				r.Badges = append(r.Badges, badge)
 			}
 		case "relatedResources":
-			r.RelatedResources = v.([]interface{})
-		case "extension":
-			r.Extension = v.(interface{})
-		case "links":
-			for _, item := range v.([]interface{}) {
-				link, err := unpackLink(item)
+			items, err := asArray(v, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			r.RelatedResources = []string{}
+			for i, item := range items {
+				id, err := asString(item, indexPath(keyPath, i))
 				if err != nil {
-					return nil, fmt.Errorf("failed to unpack %s link: %s", k, err)
+					return nil, err
 				}
-				r.Links = append(r.Links, link)
+				r.RelatedResources = append(r.RelatedResources, id)
+			}
+		case "extension":
+			if r.Extension, err = unpackExtension(v, keyPath); err != nil {
+				return nil, err
+			}
+		case "links":
+			if r.Links, err = unpackLinks(v, keyPath, d); err != nil {
+				return nil, err
 			}
 		default:
-			return nil, fmt.Errorf("map contains unsupported key: %s", k)
+			if err := d.unknownKey(path, k, v, &r.Extra); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return r, nil
 }
+
+// unpackResources unpacks the array of resources at the path.
+func unpackResources(m interface{}, path string, d *decoder) ([]*Resource, error) {
+	items, err := asArray(m, path)
+	if err != nil {
+		return nil, err
+	}
+	resources := []*Resource{}
+	for i, item := range items {
+		resource, err := unpackResource(item, indexPath(path, i), d)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// ResourcesResponse is a response with a page of resources.
+type ResourcesResponse struct {
+	Page      *PageInfo   `json:"pageInfo,omitempty"`
+	Links     []*Link     `json:"links,omitempty"`
+	Resources []*Resource `json:"resourceList,omitempty"`
+	// Lenient instructs the unpacking to preserve the keys not supported
+	// by this package in Extra fields, rather than fail.
+	Lenient bool `json:"-"`
+	// Warnings are the keys not supported by this package, found when
+	// unpacked in lenient mode.
+	Warnings []string `json:"-"`
+	// Extra holds the values of the keys not supported by this package,
+	// when unpacked in lenient mode.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON unpacks byte array into ResourcesResponse.
+func (c *ResourcesResponse) UnmarshalJSON(b []byte) error {
+	obj := "ResourcesResponse"
+	var requiredKeys = map[string]bool{
+		"resourceList": false,
+		"pageInfo":     false,
+		"links":        false,
+	}
+	var optionalKeys = map[string]bool{}
+	var m map[string]interface{}
+	if len(b) < 10 {
+		return fmt.Errorf("invalid %s data: %s", obj, b)
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", obj, err)
+	}
+
+	d := &decoder{lenient: c.Lenient}
+	for k, v := range m {
+		if _, exists := requiredKeys[k]; exists {
+			requiredKeys[k] = true
+			continue
+		}
+		if _, exists := optionalKeys[k]; exists {
+			optionalKeys[k] = true
+			continue
+		}
+		if d.lenient {
+			if err := d.unknownKey("", k, v, &c.Extra); err != nil {
+				return fmt.Errorf("failed to unpack %s: %w", obj, err)
+			}
+			continue
+		}
+		return fmt.Errorf("failed to unpack %s, found unsupported key: %s", obj, k)
+	}
+
+	for k, present := range requiredKeys {
+		if !present {
+			return fmt.Errorf("failed to unpack %s, required key not found: %s", obj, k)
+		}
+	}
+
+	p, err := unpackPageInfo(m["pageInfo"], "pageInfo", d)
+	if err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", obj, err)
+	}
+	c.Page = p
+
+	c.Links, err = unpackLinks(m["links"], "links", d)
+	if err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", obj, err)
+	}
+
+	c.Resources, err = unpackResources(m["resourceList"], "resourceList", d)
+	if err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", obj, err)
+	}
+
+	c.Warnings = d.Warnings()
+	return nil
+}
+
+// MarshalJSON packs Resource in the format of the API, e.g. the creation
+// time is in milliseconds since Unix epoch. The keys found in the response
+// the Resource was unpacked from are packed, even when their values are
+// empty, as well as the keys with values.
+func (r Resource) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.wire())
+}
+
+// wire returns Resource in the format of the API, see MarshalJSON.
+func (r *Resource) wire() map[string]interface{} {
+	w := newWireObject(r.keys)
+	w.set("identifier", r.ID, r.ID != "")
+	w.set("description", r.Description, r.Description != "")
+	w.set("creationTime", timeToEpochMillis(r.CreationTime), !r.CreationTime.IsZero())
+	w.set("resourceKey", r.Key, r.Key != nil)
+	w.set("credentialInstanceId", r.CredentialInstanceID, r.CredentialInstanceID != "")
+	if r.GeoLocation != nil {
+		w.set("geoLocation", r.GeoLocation.wire(), true)
+	}
+	if r.StatusStates != nil {
+		items := []interface{}{}
+		for _, s := range r.StatusStates {
+			items = append(items, s.wire())
+		}
+		w.set("resourceStatusStates", items, true)
+	}
+	w.set("resourceHealth", r.Health, r.Health != "")
+	w.set("resourceHealthValue", r.HealthValue, r.HealthValue != 0)
+	w.set("dtEnabled", r.DynamicThresholdEnabled, r.DynamicThresholdEnabled)
+	w.set("monitoringInterval", r.MonitoringInterval, r.MonitoringInterval != 0)
+	if r.Badges != nil {
+		items := []interface{}{}
+		for _, badge := range r.Badges {
+			items = append(items, badge.wire())
+		}
+		w.set("badges", items, true)
+	}
+	w.set("relatedResources", r.RelatedResources, r.RelatedResources != nil)
+	w.set("extension", r.Extension, len(r.Extension) > 0)
+	if r.Links != nil {
+		w.set("links", wireLinks(r.Links), true)
+	}
+	return withExtra(w.m, r.Extra)
+}
+
+// UnmarshalJSON unpacks Resource from the format of the API. The keys not
+// supported by this package are preserved in Extra, so that the Resource
+// is packed unchanged.
+func (r *Resource) UnmarshalJSON(b []byte) error {
+	var m interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("failed to unpack Resource: %s", err)
+	}
+	resource, err := unpackResource(m, "", &decoder{lenient: true})
+	if err != nil {
+		return fmt.Errorf("failed to unpack Resource: %w", err)
+	}
+	*r = *resource
+	return nil
+}
+
+// InMaintenance returns true when any of the adapter instances reports
+// the resource as being in maintenance.
+func (r *Resource) InMaintenance() bool {
+	for _, s := range r.StatusStates {
+		if s.InMaintenance() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCollecting returns true when any of the adapter instances collects
+// the resource and receives its data.
+func (r *Resource) IsCollecting() bool {
+	for _, s := range r.StatusStates {
+		if s.State == StateStarted && s.Status == StatusDataReceiving {
+			return true
+		}
+	}
+	return false
+}
+
+// Badge returns the badge of the provided type, or nil when the resource
+// has no such badge.
+func (r *Resource) Badge(t BadgeType) *Badge {
+	for _, badge := range r.Badges {
+		if badge.Type == t {
+			return badge
+		}
+	}
+	return nil
+}
+
+// BadgeScore returns the score of the badge of the provided type, and
+// whether the resource has such badge.
+func (r *Resource) BadgeScore(t BadgeType) (float64, bool) {
+	badge := r.Badge(t)
+	if badge == nil {
+		return 0, false
+	}
+	return badge.Score, true
+}
+
+// GetResource returns the Resource with the provided identifier.
+func (c *Client) GetResource(id string) (*Resource, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty resource id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.request("GET", "resources/"+id, params)
+	if err != nil {
+		return nil, err
+	}
+	var m interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	d := c.newDecoder()
+	r, err := unpackResource(m, "", d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack resource %s: %s", id, err)
+	}
+	c.logWarnings(d.Warnings())
+	return r, nil
+}
+
+// GetResources returns a list of Resource instances. The options filter
+// the resources by adapter kind (adapter_kind), resource kind
+// (resource_kind), name (name), and tags (tags, a slice of Tag).
+func (c *Client) GetResources(opts map[string]interface{}) ([]*Resource, error) {
+	params := make(map[string]string)
+	var tags []*Tag
+	for k, v := range opts {
+		var err error
+		switch k {
+		case "adapter_kind":
+			params["adapterKind"], err = stringOption(k, v)
+		case "resource_kind":
+			params["resourceKind"], err = stringOption(k, v)
+		case "name":
+			params["name"], err = stringOption(k, v)
+		case "tags":
+			var ok bool
+			if tags, ok = v.([]*Tag); !ok {
+				err = &OptionError{Option: k, Message: fmt.Sprintf("expected []*Tag, found %T", v)}
+			}
+		default:
+			err = unsupportedOption(k)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(tags) == 0 {
+		return c.getResources("resources", params)
+	}
+
+	// The filtering by tags is supported by resource query API only.
+	if err := c.requireCapability(CapabilityResourceQuery); err != nil {
+		return nil, err
+	}
+	query := make(map[string]interface{})
+	if v, exists := params["adapterKind"]; exists {
+		query["adapterKind"] = []string{v}
+	}
+	if v, exists := params["resourceKind"]; exists {
+		query["resourceKind"] = []string{v}
+	}
+	if v, exists := params["name"]; exists {
+		query["name"] = []string{v}
+	}
+	query["resourceTag"] = tags
+	return c.pageResources("POST", "resources/query", nil, query)
+}
+
+// getResources pages through the responses of a resource listing endpoint.
+func (c *Client) getResources(svc string, filter map[string]string) ([]*Resource, error) {
+	return c.pageResources("GET", svc, filter, nil)
+}
+
+// pageResources pages through the responses of a resource listing or
+// query endpoint.
+func (c *Client) pageResources(method, svc string, filter map[string]string, payload interface{}) ([]*Resource, error) {
+	resources := []*Resource{}
+	if err := c.authenticate(); err != nil {
+		return resources, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := make(map[string]string)
+		for k, v := range filter {
+			params[k] = v
+		}
+		params["page"] = strconv.Itoa(pageOffset)
+		params["pageSize"] = strconv.Itoa(pageSize)
+		b, err := c.requestWithPayload(method, svc, params, payload)
+		if err != nil {
+			return resources, err
+		}
+
+		resp := &ResourcesResponse{Lenient: c.lenientDecoding}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return resources, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+		c.logWarnings(resp.Warnings)
+
+		resources = append(resources, resp.Resources...)
+
+		if len(resp.Resources) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return resources, nil
+}
+
+// getResourcesByID returns the resources with the provided identifiers.
+func (c *Client) getResourcesByID(ids []string) ([]*Resource, error) {
+	resources := []*Resource{}
+	if err := c.authenticate(); err != nil {
+		return resources, err
+	}
+	pageSize := 100
+	for i := 0; i < len(ids); i += pageSize {
+		j := i + pageSize
+		if j > len(ids) {
+			j = len(ids)
+		}
+		params := url.Values{}
+		for _, id := range ids[i:j] {
+			params.Add("resourceId", id)
+		}
+		params.Set("page", "0")
+		params.Set("pageSize", strconv.Itoa(pageSize))
+		b, err := c.requestWithValues("GET", "resources", params, nil)
+		if err != nil {
+			return resources, err
+		}
+		resp := &ResourcesResponse{Lenient: c.lenientDecoding}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return resources, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+		c.logWarnings(resp.Warnings)
+		resources = append(resources, resp.Resources...)
+	}
+	return resources, nil
+}
+
+// getResourceProperties fetches latest properties of a resource.
+func (c *Client) getResourceProperties(id string) (map[string]string, error) {
+	properties := make(map[string]string)
+	params := make(map[string]string)
+	b, err := c.request("GET", "resources/"+id+"/properties", params)
+	if err != nil {
+		return properties, err
+	}
+
+	var resp interface{}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return properties, fmt.Errorf("failed unmarshalling GetProperties response: %s", err)
+	}
+	m, err := asObject(resp, "")
+	if err != nil {
+		return properties, fmt.Errorf("failed unmarshalling GetProperties response: %s", err)
+	}
+
+	v, exists := m["resourceId"]
+	if !exists {
+		return properties, fmt.Errorf("failed unmarshalling GetProperties response: resourceId not found")
+	}
+	resourceID, err := asString(v, "resourceId")
+	if err != nil {
+		return properties, fmt.Errorf("failed unmarshalling GetProperties response: %s", err)
+	}
+	if resourceID != id {
+		return properties, fmt.Errorf("failed unmarshalling GetProperties response: resourceId mismatch %s (expected) vs. %s (received)", id, resourceID)
+	}
+
+	if v, exists := m["property"]; exists {
+		items, err := asArray(v, "property")
+		if err != nil {
+			return properties, fmt.Errorf("failed unmarshalling GetProperties response: %s", err)
+		}
+		for i, item := range items {
+			entry, err := asObject(item, indexPath("property", i))
+			if err != nil {
+				return properties, fmt.Errorf("failed unmarshalling GetProperties response: %s", err)
+			}
+			if _, exists := entry["name"]; !exists {
+				continue
+			}
+			if _, exists := entry["value"]; !exists {
+				continue
+			}
+			name, err := asString(entry["name"], indexPath("property", i)+".name")
+			if err != nil {
+				return properties, fmt.Errorf("failed unmarshalling GetProperties response: %s", err)
+			}
+			value, err := asString(entry["value"], indexPath("property", i)+".value")
+			if err != nil {
+				return properties, fmt.Errorf("failed unmarshalling GetProperties response: %s", err)
+			}
+			properties[name] = value
+		}
+	}
+
+	return properties, nil
+}
+
+// GetResourcesProperties returns the latest properties of the resources with
+// the provided identifiers, keyed by resource identifier. The properties are
+// retrieved in bulk when the server supports it, one resource at a time
+// otherwise.
+func (c *Client) GetResourcesProperties(ids []string) (map[string]map[string]string, error) {
+	resp := make(map[string]map[string]string)
+	if len(ids) == 0 {
+		return resp, nil
+	}
+	supported, err := c.Supports(CapabilityBulkProperties)
+	if err != nil {
+		return resp, err
+	}
+	if !supported {
+		for _, id := range ids {
+			properties, err := c.getResourceProperties(id)
+			if err != nil {
+				return resp, err
+			}
+			resp[id] = properties
+		}
+		return resp, nil
+	}
+
+	for i := 0; i < len(ids); i += 100 {
+		j := i + 100
+		if j > len(ids) {
+			j = len(ids)
+		}
+		payload := map[string][]string{
+			"resourceIds": ids[i:j],
+		}
+		params := make(map[string]string)
+		b, err := c.requestWithPayload("POST", "resources/properties", params, payload)
+		if err != nil {
+			return resp, err
+		}
+		bulk := &ResourcesPropertiesResponse{}
+		if err := json.Unmarshal(b, bulk); err != nil {
+			return resp, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+		for _, entry := range bulk.Entries {
+			properties := make(map[string]string)
+			for _, p := range entry.Properties {
+				properties[p.Name] = p.Value
+			}
+			resp[entry.ResourceID] = properties
+		}
+	}
+	return resp, nil
+}
+
+// ResourcesPropertiesResponse is a response with the properties of
+// multiple resources.
+type ResourcesPropertiesResponse struct {
+	Entries []struct {
+		ResourceID string `json:"resourceId"`
+		Properties []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"property"`
+	} `json:"resourcePropertiesList"`
+}
+
+// CreateResource creates a resource with the provided key using the
+// adapter of the provided kind, e.g. OpenAPI, and returns it.
+func (c *Client) CreateResource(adapterKind string, key *ResourceKey) (*Resource, error) {
+	if adapterKind == "" {
+		return nil, fmt.Errorf("empty adapter kind")
+	}
+	if key != nil && key.AdapterKindKey == "" {
+		k := *key
+		k.AdapterKindKey = adapterKind
+		key = &k
+	}
+	if err := key.validate(); err != nil {
+		return nil, err
+	}
+	if key.AdapterKindKey != adapterKind {
+		return nil, fmt.Errorf("resource key adapter kind %s does not match %s", key.AdapterKindKey, adapterKind)
+	}
+	r := &Resource{Key: key}
+	return c.saveResource("POST", "resources/adapterkinds/"+adapterKind, r)
+}
+
+// UpdateResource updates the description, key, and settings
+// of the provided resource and returns it.
+func (c *Client) UpdateResource(r *Resource) (*Resource, error) {
+	if r == nil {
+		return nil, fmt.Errorf("nil resource")
+	}
+	if r.ID == "" {
+		return nil, fmt.Errorf("empty resource id")
+	}
+	if err := r.Key.validate(); err != nil {
+		return nil, err
+	}
+	return c.saveResource("PUT", "resources", r)
+}
+
+// DeleteResource deletes the resource with the provided identifier.
+func (c *Client) DeleteResource(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty resource id")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	if _, err := c.request("DELETE", "resources/"+id, params); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Client) saveResource(method, svc string, r *Resource) (*Resource, error) {
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	payload := r.payload()
+	if _, exists := payload["dtEnabled"]; exists {
+		supported, err := c.Supports(CapabilityDTEnabled)
+		if err != nil {
+			return nil, err
+		}
+		if !supported {
+			delete(payload, "dtEnabled")
+		}
+	}
+	params := make(map[string]string)
+	b, err := c.requestWithPayload(method, svc, params, payload)
+	if err != nil {
+		return nil, err
+	}
+	var m interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	d := c.newDecoder()
+	saved, err := unpackResource(m, "", d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack resource %s: %s", r.Key.Name, err)
+	}
+	c.logWarnings(d.Warnings())
+	return saved, nil
+}
+
+// resourceServerKeys are the keys of Resource set by the server, which are
+// not sent when the resource is created or updated.
+var resourceServerKeys = []string{
+	"creationTime",
+	"resourceStatusStates",
+	"resourceHealth",
+	"resourceHealthValue",
+	"badges",
+	"relatedResources",
+	"links",
+}
+
+// payload returns Resource in the format accepted by the API, i.e. the
+// format of MarshalJSON without the keys set by the server. The identifiers
+// of the resource key are sent with their complete types. The dynamic
+// thresholds setting is sent on update only.
+func (r *Resource) payload() map[string]interface{} {
+	m := r.wire()
+	for _, k := range resourceServerKeys {
+		delete(m, k)
+	}
+	m["resourceKey"] = r.Key.payload()
+	delete(m, "dtEnabled")
+	if r.ID != "" {
+		m["dtEnabled"] = r.DynamicThresholdEnabled
+	}
+	return m
+}
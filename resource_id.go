@@ -1,63 +1,276 @@
 package vrop
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
+)
+
+// The data types of resource identifiers.
+const (
+	IdentifierString  = "STRING"
+	IdentifierInteger = "INTEGER"
+	IdentifierDouble  = "DOUBLE"
+	IdentifierBoolean = "BOOLEAN"
 )
 
 // ResourceIdentifier is a reference to an object.
 type ResourceIdentifier struct {
-	Key   string `json:"key,omitempty"`
+	Key string `json:"key,omitempty"`
+	// The value of the identifier, formatted as a string regardless
+	// of its data type.
 	Value string `json:"value,omitempty"`
+	// The data type of the identifier, i.e. STRING, INTEGER, DOUBLE,
+	// BOOLEAN. Empty data type means STRING.
+	DataType string `json:"dataType,omitempty"`
+	// Whether the identifier is a part of the unique identity of
+	// the resource.
+	IsPartOfUniqueness bool `json:"isPartOfUniqueness,omitempty"`
+	// Extra holds the values of the keys not supported by this package,
+	// when decoded in lenient mode.
+	Extra map[string]json.RawMessage `json:"-"`
+	// keys and typeKeys are the keys of the identifier and of its type
+	// found in the response the identifier was unpacked from. valueType is
+	// the JSON type of the value in the response, e.g. number.
+	keys      keySet
+	typeKeys  keySet
+	valueType string
 }
 
-func unpackResourceIdentifier(m interface{}) (*ResourceIdentifier, error) {
-	var pm map[string]interface{}
-
-	switch m.(type) {
-	case map[string]interface{}:
-		pm = m.(map[string]interface{})
+// NewResourceIdentifier returns an instance of ResourceIdentifier. The data
+// type is derived from the value, i.e. string, int, int64, float64 or bool.
+func NewResourceIdentifier(key string, value interface{}, isPartOfUniqueness bool) (*ResourceIdentifier, error) {
+	p := &ResourceIdentifier{
+		Key:                key,
+		IsPartOfUniqueness: isPartOfUniqueness,
+	}
+	switch v := value.(type) {
+	case string:
+		p.DataType = IdentifierString
+		p.Value = v
+	case int:
+		p.DataType = IdentifierInteger
+		p.Value = strconv.Itoa(v)
+	case int64:
+		p.DataType = IdentifierInteger
+		p.Value = strconv.FormatInt(v, 10)
+	case float64:
+		p.DataType = IdentifierDouble
+		p.Value = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		p.DataType = IdentifierBoolean
+		p.Value = strconv.FormatBool(v)
 	default:
-		return nil, fmt.Errorf("map not found")
+		return nil, fmt.Errorf("resource id %s has unsupported value type: %T", key, value)
 	}
+	return p, nil
+}
 
-	p := &ResourceIdentifier{}
+func unpackResourceIdentifier(m interface{}, path string, d *decoder) (*ResourceIdentifier, error) {
+	pm, err := asObject(m, path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ResourceIdentifier{keys: newKeySet(pm)}
+	if d != nil && d.lenient {
+		for k, v := range pm {
+			switch k {
+			case "identifierType", "value":
+			default:
+				if err := d.unknownKey(path, k, v, &p.Extra); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
 
 	var name, dataType, value string
-	// var isPartOfUniqueness bool
+	var isPartOfUniqueness bool
 	if pv, exists := pm["identifierType"]; exists {
-		it := pv.(map[string]interface{})
+		typePath := joinPath(path, "identifierType")
+		it, err := asObject(pv, typePath)
+		if err != nil {
+			return nil, err
+		}
+		p.typeKeys = newKeySet(it)
 		for k, v := range it {
 			switch k {
 			case "name":
-				name = v.(string)
+				if name, err = asString(v, joinPath(typePath, k)); err != nil {
+					return nil, err
+				}
 			case "dataType":
-				dataType = v.(string)
+				if dataType, err = asString(v, joinPath(typePath, k)); err != nil {
+					return nil, err
+				}
 			case "isPartOfUniqueness":
-				// isPartOfUniqueness = v.(bool)
+				if isPartOfUniqueness, err = asBool(v, joinPath(typePath, k)); err != nil {
+					return nil, err
+				}
 			default:
-				return nil, fmt.Errorf("resource id contains unsupported identifierType: %s, data: %v", k, m)
+				if err := d.unknownKey(typePath, k, v, nil); err != nil {
+					return nil, err
+				}
 			}
 		}
 	}
 
+	switch dataType {
+	case "", IdentifierString, IdentifierInteger, IdentifierDouble, IdentifierBoolean:
+	default:
+		return nil, decodeErrorf(joinPath(path, "identifierType.dataType"), "unsupported data type: %s", dataType)
+	}
+
 	if pv, exists := pm["value"]; exists {
-		switch dataType {
-		case "STRING":
-			value = pv.(string)
+		valuePath := joinPath(path, "value")
+		p.valueType = jsonTypeName(pv)
+		// The values are typically strings, regardless of the data type.
+		switch v := pv.(type) {
+		case string:
+			value = v
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			value = strconv.FormatBool(v)
 		default:
-			return nil, fmt.Errorf("resource id contains unsupported type: %s, data: %v", dataType, m)
+			return nil, decodeErrorf(valuePath, "expected string, found %s", jsonTypeName(pv))
+		}
+		if value != "" {
+			if _, err := parseIdentifierValue(dataType, value); err != nil {
+				return nil, decodeErrorf(valuePath, "%s", err)
+			}
 		}
 	}
 
 	if name == "" {
-		return nil, fmt.Errorf("resource id name not found, data: %v", m)
+		return nil, decodeErrorf(joinPath(path, "identifierType.name"), "resource id name not found")
 	}
-	//if value == "" {
-	//	return nil, fmt.Errorf("resource id value not found, data: %v", m)
-	//}
 
 	p.Key = name
 	p.Value = value
+	p.DataType = dataType
+	p.IsPartOfUniqueness = isPartOfUniqueness
 
 	return p, nil
 }
+
+// TypedValue returns the value of the identifier converted to its data
+// type, i.e. string, int64, float64 or bool.
+func (p *ResourceIdentifier) TypedValue() (interface{}, error) {
+	v, err := parseIdentifierValue(p.dataType(), p.Value)
+	if err != nil {
+		return nil, fmt.Errorf("resource id %s: %s", p.Key, err)
+	}
+	return v, nil
+}
+
+// Equal returns true when the identifiers have the same key and the same
+// typed value, e.g. INTEGER values 1 and 01.
+func (p *ResourceIdentifier) Equal(other *ResourceIdentifier) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+	if p.Key != other.Key || p.dataType() != other.dataType() {
+		return false
+	}
+	a, err := p.TypedValue()
+	if err != nil {
+		return p.Value == other.Value
+	}
+	b, err := other.TypedValue()
+	if err != nil {
+		return false
+	}
+	return a == b
+}
+
+func (p *ResourceIdentifier) dataType() string {
+	if p.DataType == "" {
+		return IdentifierString
+	}
+	return p.DataType
+}
+
+// MarshalJSON packs ResourceIdentifier in the format of the API. The keys
+// found in the response the identifier was unpacked from are packed, even
+// when their values are empty, as well as the keys with values. The value
+// is packed with its JSON type in the response, e.g. number.
+func (p ResourceIdentifier) MarshalJSON() ([]byte, error) {
+	it := newWireObject(p.typeKeys)
+	it.set("name", p.Key, p.Key != "")
+	it.set("dataType", p.DataType, p.DataType != "")
+	it.set("isPartOfUniqueness", p.IsPartOfUniqueness, p.IsPartOfUniqueness)
+	w := newWireObject(p.keys)
+	w.set("identifierType", it.m, len(it.m) > 0)
+	w.set("value", p.wireValue(), p.Value != "")
+	return json.Marshal(withExtra(w.m, p.Extra))
+}
+
+// wireValue returns the value of the identifier with its JSON type in
+// the response the identifier was unpacked from.
+func (p *ResourceIdentifier) wireValue() interface{} {
+	switch p.valueType {
+	case "number":
+		if _, err := strconv.ParseFloat(p.Value, 64); err == nil {
+			return json.Number(p.Value)
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(p.Value); err == nil {
+			return v
+		}
+	}
+	return p.Value
+}
+
+// UnmarshalJSON unpacks ResourceIdentifier from the format of the API.
+func (p *ResourceIdentifier) UnmarshalJSON(b []byte) error {
+	var m interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("failed to unpack ResourceIdentifier: %s", err)
+	}
+	id, err := unpackResourceIdentifier(m, "", &decoder{lenient: true})
+	if err != nil {
+		return fmt.Errorf("failed to unpack ResourceIdentifier: %w", err)
+	}
+	*p = *id
+	return nil
+}
+
+func (p *ResourceIdentifier) payload() map[string]interface{} {
+	return map[string]interface{}{
+		"identifierType": map[string]interface{}{
+			"name":               p.Key,
+			"dataType":           p.dataType(),
+			"isPartOfUniqueness": p.IsPartOfUniqueness,
+		},
+		"value": p.Value,
+	}
+}
+
+// parseIdentifierValue converts the value of an identifier to its data type.
+func parseIdentifierValue(dataType, value string) (interface{}, error) {
+	switch dataType {
+	case "", IdentifierString:
+		return value, nil
+	case IdentifierInteger:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %s", dataType, value)
+		}
+		return v, nil
+	case IdentifierDouble:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %s", dataType, value)
+		}
+		return v, nil
+	case IdentifierBoolean:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %s", dataType, value)
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("unsupported data type: %s", dataType)
+}
@@ -1,6 +1,7 @@
 package vrop
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -17,51 +18,183 @@ type ResourceKey struct {
 	ResourceIdentifiers []*ResourceIdentifier `json:"resourceIdentifiers,omitempty"`
 	// Set of useful links related to the current object.
 	Links []*Link `json:"links,omitempty"`
-	// Extension values that were added to the given object by third-party.
-	Extension interface{} `json:"extension,omitempty"`
+	// Extension values that were added to the given object by third-party,
+	// preserved as raw JSON. See DecodeExtension.
+	Extension json.RawMessage `json:"extension,omitempty"`
+	// Extra holds the values of the keys not supported by this package,
+	// when decoded in lenient mode.
+	Extra map[string]json.RawMessage `json:"-"`
+	// keys are the keys found in the response the ResourceKey was
+	// unpacked from.
+	keys keySet
 }
 
-func unpackResourceKey(m interface{}) (*ResourceKey, error) {
-	var pim map[string]interface{}
-
-	switch m.(type) {
-	case map[string]interface{}:
-		pim = m.(map[string]interface{})
-	default:
-		return nil, fmt.Errorf("map not found")
+func unpackResourceKey(m interface{}, path string, d *decoder) (*ResourceKey, error) {
+	pim, err := asObject(m, path)
+	if err != nil {
+		return nil, err
 	}
 
-	p := &ResourceKey{}
+	p := &ResourceKey{keys: newKeySet(pim)}
 	for k, v := range pim {
 		switch k {
 		case "name":
-			p.Name = v.(string)
+			if p.Name, err = asString(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		case "adapterKindKey":
-			p.AdapterKindKey = v.(string)
+			if p.AdapterKindKey, err = asString(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		case "resourceKindKey":
-			p.ResourceKindKey = v.(string)
+			if p.ResourceKindKey, err = asString(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		case "resourceIdentifiers":
-			for _, item := range v.([]interface{}) {
-				resourceID, err := unpackResourceIdentifier(item)
+			items, err := asArray(v, joinPath(path, k))
+			if err != nil {
+				return nil, err
+			}
+			p.ResourceIdentifiers = []*ResourceIdentifier{}
+			for i, item := range items {
+				resourceID, err := unpackResourceIdentifier(item, indexPath(joinPath(path, k), i), d)
 				if err != nil {
-					return nil, fmt.Errorf("failed to unpack %s resourceIdentifier: %s", k, err)
+					return nil, err
 				}
 				p.ResourceIdentifiers = append(p.ResourceIdentifiers, resourceID)
 			}
 		case "links":
-			for _, item := range v.([]interface{}) {
-				link, err := unpackLink(item)
-				if err != nil {
-					return nil, fmt.Errorf("failed to unpack %s link: %s", k, err)
-				}
-				p.Links = append(p.Links, link)
+			if p.Links, err = unpackLinks(v, joinPath(path, k), d); err != nil {
+				return nil, err
 			}
 		case "extension":
-			// TODO
+			if p.Extension, err = unpackExtension(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		default:
-			return nil, fmt.Errorf("map contains unsupported key: %s", k)
+			if err := d.unknownKey(path, k, v, &p.Extra); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return p, nil
 }
+
+// MarshalJSON packs ResourceKey in the format of the API. The keys found
+// in the response the ResourceKey was unpacked from are packed, even when
+// their values are empty, as well as the keys with values.
+func (p ResourceKey) MarshalJSON() ([]byte, error) {
+	w := newWireObject(p.keys)
+	w.set("name", p.Name, p.Name != "")
+	w.set("adapterKindKey", p.AdapterKindKey, p.AdapterKindKey != "")
+	w.set("resourceKindKey", p.ResourceKindKey, p.ResourceKindKey != "")
+	w.set("resourceIdentifiers", p.ResourceIdentifiers, p.ResourceIdentifiers != nil)
+	if p.Links != nil {
+		w.set("links", wireLinks(p.Links), true)
+	}
+	w.set("extension", p.Extension, len(p.Extension) > 0)
+	return json.Marshal(withExtra(w.m, p.Extra))
+}
+
+// UnmarshalJSON unpacks ResourceKey from the format of the API. The keys
+// not supported by this package are preserved in Extra.
+func (p *ResourceKey) UnmarshalJSON(b []byte) error {
+	var m interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("failed to unpack ResourceKey: %s", err)
+	}
+	key, err := unpackResourceKey(m, "", &decoder{lenient: true})
+	if err != nil {
+		return fmt.Errorf("failed to unpack ResourceKey: %w", err)
+	}
+	*p = *key
+	return nil
+}
+
+// payload returns ResourceKey in the format accepted by the API.
+func (p *ResourceKey) payload() map[string]interface{} {
+	m := map[string]interface{}{
+		"name":            p.Name,
+		"adapterKindKey":  p.AdapterKindKey,
+		"resourceKindKey": p.ResourceKindKey,
+	}
+	ids := []interface{}{}
+	for _, id := range p.ResourceIdentifiers {
+		ids = append(ids, id.payload())
+	}
+	m["resourceIdentifiers"] = ids
+	if len(p.Extension) > 0 {
+		m["extension"] = p.Extension
+	}
+	return m
+}
+
+func (p *ResourceKey) validate() error {
+	if p == nil {
+		return fmt.Errorf("nil resource key")
+	}
+	if p.Name == "" {
+		return fmt.Errorf("resource key name is empty")
+	}
+	if p.AdapterKindKey == "" {
+		return fmt.Errorf("resource key %s has no adapter kind", p.Name)
+	}
+	if p.ResourceKindKey == "" {
+		return fmt.Errorf("resource key %s has no resource kind", p.Name)
+	}
+	for _, id := range p.ResourceIdentifiers {
+		if id.Key == "" {
+			return fmt.Errorf("resource key %s has identifier with empty name", p.Name)
+		}
+		if id.Value == "" {
+			continue
+		}
+		if _, err := id.TypedValue(); err != nil {
+			return fmt.Errorf("resource key %s has invalid identifier: %s", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// Identifier returns the identifier with the provided key, or nil when
+// the resource key has no such identifier.
+func (p *ResourceKey) Identifier(key string) *ResourceIdentifier {
+	for _, id := range p.ResourceIdentifiers {
+		if id.Key == key {
+			return id
+		}
+	}
+	return nil
+}
+
+// MatchesIdentifiers returns true when the resource keys have the same
+// adapter kind, resource kind, and identifiers being a part of the unique
+// identity of the resource.
+func (p *ResourceKey) MatchesIdentifiers(other *ResourceKey) bool {
+	if p == nil || other == nil {
+		return false
+	}
+	if p.AdapterKindKey != other.AdapterKindKey || p.ResourceKindKey != other.ResourceKindKey {
+		return false
+	}
+	unique := func(k *ResourceKey) []*ResourceIdentifier {
+		ids := []*ResourceIdentifier{}
+		for _, id := range k.ResourceIdentifiers {
+			if id.IsPartOfUniqueness {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	ids, otherIDs := unique(p), unique(other)
+	if len(ids) == 0 || len(ids) != len(otherIDs) {
+		return false
+	}
+	for _, id := range ids {
+		if !id.Equal(other.Identifier(id.Key)) {
+			return false
+		}
+	}
+	return true
+}
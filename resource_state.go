@@ -1,6 +1,7 @@
 package vrop
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -11,11 +12,14 @@ type ResourceStatusState struct {
 	// A human readable status message
 	Message string `json:"statusMessage,omitempty"`
 	// The resource state.
+	// STARTED: Resource is collecting
 	// STOPPED: Resource is not collecting
+	// MAINTAINED: Resource is in scheduled maintenance
+	// MAINTAINED_MANUAL: Resource is in maintenance until unmarked
 	// NOT_EXISTING: Non-existing resource
 	// NONE: Resource not associated with an adapter instance.
 	// UNKNOWN: Serves as a means to ensure older clients can talk to newer servers
-	State string `json:"resourceState,omitempty"`
+	State ResourceState `json:"resourceState,omitempty"`
 	// The resource data collection status
 	// NONE: initial status of resource.
 	// ERROR: error happened while collecting data
@@ -26,34 +30,77 @@ type ResourceStatusState struct {
 	// NO_DATA_RECEIVING: no data receiving
 	// NO_PARENT_MONITORING: no parent adapter instance resource is monitoring
 	// COLLECTOR_DOWN: collector is down
-	Status string `json:"resourceStatus,omitempty"`
+	Status ResourceStatus `json:"resourceStatus,omitempty"`
+	// Extra holds the values of the keys not supported by this package,
+	// when decoded in lenient mode.
+	Extra map[string]json.RawMessage `json:"-"`
+	// keys are the keys found in the response the ResourceStatusState was unpacked from.
+	keys keySet
 }
 
-func unpackResourceStatusState(m interface{}) (*ResourceStatusState, error) {
-	var rssm map[string]interface{}
-
-	switch m.(type) {
-	case map[string]interface{}:
-		rssm = m.(map[string]interface{})
-	default:
-		return nil, fmt.Errorf("map not found")
+func unpackResourceStatusState(m interface{}, path string, d *decoder) (*ResourceStatusState, error) {
+	rssm, err := asObject(m, path)
+	if err != nil {
+		return nil, err
 	}
 
-	r := &ResourceStatusState{}
+	r := &ResourceStatusState{keys: newKeySet(rssm)}
 	for k, v := range rssm {
 		switch k {
 		case "adapterInstanceId":
-			r.AdapterInstanceID = v.(string)
+			if r.AdapterInstanceID, err = asString(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		case "statusMessage":
-			r.Message = v.(string)
+			if r.Message, err = asString(v, joinPath(path, k)); err != nil {
+				return nil, err
+			}
 		case "resourceState":
-			r.State = v.(string)
+			s, err := d.enum(v, joinPath(path, k), func(s string) bool { return ResourceState(s).Valid() })
+			if err != nil {
+				return nil, err
+			}
+			r.State = ResourceState(s)
 		case "resourceStatus":
-			r.Status = v.(string)
+			s, err := d.enum(v, joinPath(path, k), func(s string) bool { return ResourceStatus(s).Valid() })
+			if err != nil {
+				return nil, err
+			}
+			r.Status = ResourceStatus(s)
 		default:
-			return nil, fmt.Errorf("map contains unsupported key: %s", k)
+			if err := d.unknownKey(path, k, v, &r.Extra); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return r, nil
 }
+
+// wire returns ResourceStatusState in the format of the API.
+func (r *ResourceStatusState) wire() map[string]interface{} {
+	w := newWireObject(r.keys)
+	w.set("adapterInstanceId", r.AdapterInstanceID, r.AdapterInstanceID != "")
+	w.set("statusMessage", r.Message, r.Message != "")
+	w.set("resourceState", r.State, r.State != "")
+	w.set("resourceStatus", r.Status, r.Status != "")
+	return withExtra(w.m, r.Extra)
+}
+
+// InMaintenance returns true when the resource is in maintenance.
+func (r *ResourceStatusState) InMaintenance() bool {
+	switch r.State {
+	case StateMaintained, StateMaintainedManual:
+		return true
+	}
+	return false
+}
+
+// GetAdapterInstanceName returns the name of the adapter instance
+// reporting the status and state.
+func (r *ResourceStatusState) GetAdapterInstanceName(c *Client) (string, error) {
+	if r.AdapterInstanceID == "" {
+		return "", fmt.Errorf("adapter instance id is empty")
+	}
+	return c.GetAdapterInstanceName(r.AdapterInstanceID)
+}
@@ -0,0 +1,195 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResourceSummary is a normalized summary of a resource of any kind. The
+// names of its JSON fields are also the names of its CSV columns.
+type ResourceSummary struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	AdapterKind  string `json:"adapter_kind"`
+	ResourceKind string `json:"resource_kind"`
+	// The health of the resource, e.g. GREEN, and its score.
+	Health      Color   `json:"health"`
+	HealthScore float64 `json:"health_score"`
+	// The scores of the badges of the resource. The score is null when
+	// the resource has no such badge.
+	RiskScore              *float64 `json:"risk_score"`
+	EfficiencyScore        *float64 `json:"efficiency_score"`
+	WorkloadScore          *float64 `json:"workload_score"`
+	CapacityRemainingScore *float64 `json:"capacity_remaining_score"`
+	TimeRemainingScore     *float64 `json:"time_remaining_score"`
+	// The collection state and status of the resource, e.g. STARTED and
+	// DATA_RECEIVING.
+	CollectionState  ResourceState  `json:"collection_state"`
+	CollectionStatus ResourceStatus `json:"collection_status"`
+	IsCollecting     bool           `json:"is_collecting"`
+	InMaintenance    bool           `json:"in_maintenance"`
+	// The time the resource was created. It is null when unknown.
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+// NewResourceSummary returns the summary of the resource.
+func NewResourceSummary(r *Resource) *ResourceSummary {
+	s := &ResourceSummary{
+		ID:            r.ID,
+		Health:        r.Health,
+		HealthScore:   r.HealthValue,
+		IsCollecting:  r.IsCollecting(),
+		InMaintenance: r.InMaintenance(),
+	}
+	if !r.CreationTime.IsZero() {
+		createdAt := r.CreationTime.UTC()
+		s.CreatedAt = &createdAt
+	}
+	if r.Key != nil {
+		s.Name = r.Key.Name
+		s.AdapterKind = r.Key.AdapterKindKey
+		s.ResourceKind = r.Key.ResourceKindKey
+	}
+	// The score of the health badge is used when the health value is
+	// absent from the response, rather than when it is zero.
+	if !r.keys["resourceHealthValue"] && r.HealthValue == 0 {
+		if score, found := r.BadgeScore(BadgeHealth); found {
+			s.HealthScore = score
+		}
+	}
+	badgeScore := func(t BadgeType) *float64 {
+		if score, found := r.BadgeScore(t); found {
+			return &score
+		}
+		return nil
+	}
+	s.RiskScore = badgeScore(BadgeRisk)
+	s.EfficiencyScore = badgeScore(BadgeEfficiency)
+	s.WorkloadScore = badgeScore(BadgeWorkload)
+	s.CapacityRemainingScore = badgeScore(BadgeCapacityRemaining)
+	s.TimeRemainingScore = badgeScore(BadgeTimeRemaining)
+
+	// The state and status reported by the adapter instance receiving the
+	// data of the resource take precedence.
+	for _, state := range r.StatusStates {
+		if s.CollectionState == "" || state.Status == StatusDataReceiving {
+			s.CollectionState = state.State
+			s.CollectionStatus = state.Status
+		}
+		if state.Status == StatusDataReceiving {
+			break
+		}
+	}
+	return s
+}
+
+// GetResourceSummaries returns the summaries of the resources selected by
+// the options of GetResources.
+func (c *Client) GetResourceSummaries(opts map[string]interface{}) ([]*ResourceSummary, error) {
+	summaries := []*ResourceSummary{}
+	resources, err := c.GetResources(opts)
+	if err != nil {
+		return summaries, err
+	}
+	for _, r := range resources {
+		summaries = append(summaries, NewResourceSummary(r))
+	}
+	return summaries, nil
+}
+
+// ResourceSummaryHeader returns the names of the CSV columns of
+// ResourceSummary, i.e. the names of its JSON fields.
+func ResourceSummaryHeader() []string {
+	header := []string{}
+	t := reflect.TypeOf(ResourceSummary{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		header = append(header, name)
+	}
+	return header
+}
+
+// Record returns ResourceSummary as a CSV record, in the order of the
+// columns returned by ResourceSummaryHeader. Null values are empty.
+func (s *ResourceSummary) Record() []string {
+	record := []string{}
+	v := reflect.ValueOf(s).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		record = append(record, formatSummaryValue(v.Field(i).Interface()))
+	}
+	return record
+}
+
+// WriteResourceSummariesCSV writes the summaries in CSV format, with the
+// header in the first row.
+func WriteResourceSummariesCSV(w io.Writer, summaries []*ResourceSummary) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ResourceSummaryHeader()); err != nil {
+		return fmt.Errorf("failed writing csv: %s", err)
+	}
+	for _, s := range summaries {
+		if err := cw.Write(s.Record()); err != nil {
+			return fmt.Errorf("failed writing csv: %s", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed writing csv: %s", err)
+	}
+	return nil
+}
+
+// ToJSONString serializes ResourceSummary to a string.
+func (s *ResourceSummary) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
+
+// formatSummaryValue formats the value of a field of ResourceSummary
+// for CSV output.
+func formatSummaryValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case *time.Time:
+		if value == nil {
+			return ""
+		}
+		return value.UTC().Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case *float64:
+		if value == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,389 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"errors"
+	. "github.com/greenpau/go-vrop/internal/server"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// TestResourceRoundTrip verifies that the resources in the API responses
+// are packed back unchanged.
+func TestResourceRoundTrip(t *testing.T) {
+	for _, fileName := range []string{"virtual_machines.json", "resources.json", "resources_sparse.json"} {
+		b, err := ioutil.ReadFile("testdata/responses/" + fileName)
+		if err != nil {
+			t.Fatalf("failed reading test data: %s", err)
+		}
+		var golden struct {
+			Resources []interface{} `json:"resourceList"`
+		}
+		if err := json.Unmarshal(b, &golden); err != nil {
+			t.Fatalf("%s: failed unmarshalling test data: %s", fileName, err)
+		}
+		resp := &ResourcesResponse{}
+		if err := json.Unmarshal(b, resp); err != nil {
+			t.Fatalf("%s: failed unpacking test data: %s", fileName, err)
+		}
+		if len(resp.Resources) != len(golden.Resources) {
+			t.Fatalf("%s: expected %d resources, got %d", fileName, len(golden.Resources), len(resp.Resources))
+		}
+		for i, r := range resp.Resources {
+			// Pack the resource, e.g. to cache it to disk, and unpack it.
+			cached, err := json.Marshal(r)
+			if err != nil {
+				t.Fatalf("%s: resource %d: failed packing: %s", fileName, i, err)
+			}
+			restored := &Resource{}
+			if err := json.Unmarshal(cached, restored); err != nil {
+				t.Fatalf("%s: resource %d: failed unpacking: %s", fileName, i, err)
+			}
+			if !reflect.DeepEqual(r, restored) {
+				t.Fatalf("%s: resource %d: unpacked resource differs:\n%+v\n%+v", fileName, i, r, restored)
+			}
+			sent, err := json.Marshal(restored)
+			if err != nil {
+				t.Fatalf("%s: resource %d: failed packing: %s", fileName, i, err)
+			}
+			var got interface{}
+			if err := json.Unmarshal(sent, &got); err != nil {
+				t.Fatalf("%s: resource %d: failed unmarshalling: %s", fileName, i, err)
+			}
+			if !reflect.DeepEqual(got, golden.Resources[i]) {
+				want, _ := json.Marshal(golden.Resources[i])
+				t.Fatalf("%s: resource %d: packed resource differs:\ngot:  %s\nwant: %s", fileName, i, sent, want)
+			}
+			// Pack the resource by value, e.g. in a slice of resources.
+			sent, err = json.Marshal([]Resource{*restored})
+			if err != nil {
+				t.Fatalf("%s: resource %d: failed packing: %s", fileName, i, err)
+			}
+			var gotList []interface{}
+			if err := json.Unmarshal(sent, &gotList); err != nil {
+				t.Fatalf("%s: resource %d: failed unmarshalling: %s", fileName, i, err)
+			}
+			if !reflect.DeepEqual(gotList[0], golden.Resources[i]) {
+				want, _ := json.Marshal(golden.Resources[i])
+				t.Fatalf("%s: resource %d: resource packed by value differs:\ngot:  %s\nwant: %s", fileName, i, sent, want)
+			}
+		}
+	}
+}
+
+func TestResourceEnums(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/responses/resources.json")
+	if err != nil {
+		t.Fatalf("failed reading test data: %s", err)
+	}
+	resp := &ResourcesResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		t.Fatalf("failed unpacking test data: %s", err)
+	}
+	r := resp.Resources[0]
+	if r.Health != ColorYellow || r.Health.String() != "YELLOW" {
+		t.Fatalf("unexpected health: %s", r.Health)
+	}
+	if !r.IsCollecting() {
+		t.Fatalf("expected resource %s to be collecting", r.ID)
+	}
+	if score, found := r.BadgeScore(BadgeRisk); !found || score != 0 {
+		t.Fatalf("unexpected risk badge score: %v, %t", score, found)
+	}
+	if _, found := r.BadgeScore(BadgeEfficiency); found {
+		t.Fatalf("unexpected efficiency badge")
+	}
+	if resp.Resources[1].IsCollecting() {
+		t.Fatalf("expected resource %s in maintenance not to be collecting", resp.Resources[1].ID)
+	}
+
+	// The values unknown to this package are kept as-is, with a warning,
+	// in both strict and lenient modes.
+	var m interface{}
+	data := `{"type":"SUSTAINABILITY","color":"GREEN","score":1}`
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		t.Fatalf("failed unmarshalling test data: %s", err)
+	}
+	for _, lenient := range []bool{false, true} {
+		d := &decoder{lenient: lenient}
+		badge, err := unpackBadge(m, "badges[0]", d)
+		if err != nil {
+			t.Fatalf("failed unpacking badge, lenient %t: %s", lenient, err)
+		}
+		if badge.Type != BadgeType("SUSTAINABILITY") || badge.Type.Valid() || len(d.Warnings()) != 1 {
+			t.Fatalf("unexpected badge, lenient %t: %+v, warnings: %v", lenient, badge, d.Warnings())
+		}
+	}
+
+	// The response with unknown values is unpacked in strict mode.
+	var resources map[string]interface{}
+	if err := json.Unmarshal(b, &resources); err != nil {
+		t.Fatalf("failed unmarshalling test data: %s", err)
+	}
+	resource := resources["resourceList"].([]interface{})[0].(map[string]interface{})
+	resource["resourceHealth"] = "PURPLE"
+	state := resource["resourceStatusStates"].([]interface{})[0].(map[string]interface{})
+	state["resourceState"] = "HIBERNATING"
+	if b, err = json.Marshal(resources); err != nil {
+		t.Fatalf("failed marshalling test data: %s", err)
+	}
+	resp = &ResourcesResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		t.Fatalf("failed unpacking unknown values in strict mode: %s", err)
+	}
+	r = resp.Resources[0]
+	if r.Health != Color("PURPLE") || r.StatusStates[0].State != ResourceState("HIBERNATING") {
+		t.Fatalf("unexpected values: %s, %s", r.Health, r.StatusStates[0].State)
+	}
+	if len(resp.Warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %d: %v", len(resp.Warnings), resp.Warnings)
+	}
+}
+
+func TestResourceSummary(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/responses/resources.json")
+	if err != nil {
+		t.Fatalf("failed reading test data: %s", err)
+	}
+	resp := &ResourcesResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		t.Fatalf("failed unpacking test data: %s", err)
+	}
+	summaries := []*ResourceSummary{}
+	for _, r := range resp.Resources {
+		summaries = append(summaries, NewResourceSummary(r))
+	}
+	s := summaries[0]
+	if s.Name != "ls-web-01" || s.ResourceKind != "LogicalSwitch" || s.HealthScore != 75 {
+		t.Fatalf("unexpected summary: %+v", s)
+	}
+	if s.RiskScore == nil || *s.RiskScore != 0 || s.WorkloadScore != nil {
+		t.Fatalf("unexpected badge scores: %+v", s)
+	}
+	if s.CollectionStatus != StatusDataReceiving || !s.IsCollecting || summaries[1].IsCollecting || !summaries[1].InMaintenance {
+		t.Fatalf("unexpected collection status: %+v", summaries)
+	}
+
+	// The CSV columns match the JSON fields.
+	header := ResourceSummaryHeader()
+	var m map[string]interface{}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("failed marshalling summary: %s", err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("failed unmarshalling summary: %s", err)
+	}
+	if len(header) != len(m) {
+		t.Fatalf("expected %d columns, got %d: %v", len(m), len(header), header)
+	}
+	record := s.Record()
+	for i, column := range header {
+		if _, exists := m[column]; !exists {
+			t.Fatalf("column %s not found in json: %s", column, data)
+		}
+		if column == "created_at" && record[i] != m[column] {
+			t.Fatalf("column %s: csv value %s differs from json value %v", column, record[i], m[column])
+		}
+		if column == "workload_score" && record[i] != "" {
+			t.Fatalf("column %s: expected empty value, got %s", column, record[i])
+		}
+	}
+}
+
+func TestResourceSummaryHealthScore(t *testing.T) {
+	testcases := []struct {
+		name  string
+		data  string
+		score float64
+	}{
+		{
+			name:  "health value of zero",
+			data:  `{"identifier":"r1","resourceHealthValue":0,"badges":[{"type":"HEALTH","score":75}]}`,
+			score: 0,
+		},
+		{
+			name:  "health value",
+			data:  `{"identifier":"r1","resourceHealthValue":50,"badges":[{"type":"HEALTH","score":75}]}`,
+			score: 50,
+		},
+		{
+			name:  "health value absent",
+			data:  `{"identifier":"r1","badges":[{"type":"HEALTH","score":75}]}`,
+			score: 75,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Resource{}
+			if err := json.Unmarshal([]byte(tc.data), r); err != nil {
+				t.Fatalf("failed unpacking resource: %s", err)
+			}
+			if s := NewResourceSummary(r); s.HealthScore != tc.score {
+				t.Fatalf("expected health score %v, got %v", tc.score, s.HealthScore)
+			}
+		})
+	}
+}
+
+func TestResourceSummaryCreatedAt(t *testing.T) {
+	r := &Resource{ID: "r1"}
+	s := NewResourceSummary(r)
+	var m map[string]interface{}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("failed marshalling summary: %s", err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("failed unmarshalling summary: %s", err)
+	}
+	// The unknown creation time is null in JSON and empty in CSV.
+	if v, exists := m["created_at"]; !exists || v != nil {
+		t.Fatalf("expected null created_at, got: %s", data)
+	}
+	for i, column := range ResourceSummaryHeader() {
+		if column == "created_at" && s.Record()[i] != "" {
+			t.Fatalf("expected empty created_at, got %s", s.Record()[i])
+		}
+	}
+}
+
+func TestOptionError(t *testing.T) {
+	cli, err := NewClient(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("failed initializing client: %s", err)
+	}
+	defer cli.Close()
+	for _, opts := range []map[string]interface{}{
+		{"adapter_kind": 1},
+		{"name": []string{"vm1"}},
+		{"tags": []Tag{}},
+		{"foo": "bar"},
+	} {
+		_, err := cli.GetResources(opts)
+		var optErr *OptionError
+		if !errors.As(err, &optErr) {
+			t.Fatalf("expected option error for %v, got: %v", opts, err)
+		}
+	}
+	if _, err := definitionFilter(map[string]interface{}{"resource_kind": true}); err == nil {
+		t.Fatalf("expected option error, got none")
+	}
+}
+
+func TestSaveResource(t *testing.T) {
+	id := "5d4c3b2a-1908-47f6-a5b4-c3d2e1f0a9b8"
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/versions/current": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "versions_current.json"},
+		},
+		"/suite-api/api/resources/adapterkinds/OpenAPI": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "POST", FileName: "resource_openapi.json"},
+		},
+		"/suite-api/api/resources": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "PUT", FileName: "resource_openapi.json"},
+		},
+		"/suite-api/api/resources/" + id: []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "DELETE"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+	// The response has a key added by a newer version of the server.
+	cli.SetLenientDecoding()
+
+	wantKey := map[string]interface{}{
+		"name":            "app-01",
+		"adapterKindKey":  "OpenAPI",
+		"resourceKindKey": "Application",
+		"resourceIdentifiers": []interface{}{
+			map[string]interface{}{
+				"identifierType": map[string]interface{}{
+					"name":               "APP_ID",
+					"dataType":           "INTEGER",
+					"isPartOfUniqueness": true,
+				},
+				"value": "1001",
+			},
+		},
+	}
+
+	appID, err := NewResourceIdentifier("APP_ID", 1001, true)
+	if err != nil {
+		t.Fatalf("failed creating resource identifier: %s", err)
+	}
+	key := &ResourceKey{
+		Name:                "app-01",
+		ResourceKindKey:     "Application",
+		ResourceIdentifiers: []*ResourceIdentifier{appID},
+	}
+	created, err := cli.CreateResource("OpenAPI", key)
+	if err != nil {
+		t.Fatalf("failed creating resource: %s", err)
+	}
+	if created.ID != id {
+		t.Fatalf("unexpected created resource id: %s", created.ID)
+	}
+	payload := map[string]interface{}{}
+	req := lastRequest(t, server, &payload)
+	if req.Method != "POST" {
+		t.Fatalf("unexpected request method: %s", req.Method)
+	}
+	want := map[string]interface{}{"resourceKey": wantKey}
+	if !reflect.DeepEqual(payload, want) {
+		t.Fatalf("unexpected create payload:\ngot:  %v\nwant: %v", payload, want)
+	}
+	if _, err := cli.CreateResource("VMWARE", key); err == nil {
+		t.Fatalf("expected error for mismatched adapter kind, got none")
+	}
+
+	// The keys set by the server are not sent on update, while the keys
+	// not supported by this package are preserved.
+	created.Description = "Billing application, production"
+	created.DynamicThresholdEnabled = false
+	if _, err := cli.UpdateResource(created); err != nil {
+		t.Fatalf("failed updating resource: %s", err)
+	}
+	payload = map[string]interface{}{}
+	req = lastRequest(t, server, &payload)
+	if req.Method != "PUT" {
+		t.Fatalf("unexpected request method: %s", req.Method)
+	}
+	want = map[string]interface{}{
+		"identifier":         id,
+		"description":        "Billing application, production",
+		"resourceKey":        wantKey,
+		"dtEnabled":          false,
+		"monitoringInterval": float64(5),
+		"owner":              "billing-team",
+	}
+	if !reflect.DeepEqual(payload, want) {
+		t.Fatalf("unexpected update payload:\ngot:  %v\nwant: %v", payload, want)
+	}
+	if _, err := cli.UpdateResource(&Resource{Key: key}); err == nil {
+		t.Fatalf("expected error for resource without id, got none")
+	}
+
+	if err := cli.DeleteResource(id); err != nil {
+		t.Fatalf("failed deleting resource: %s", err)
+	}
+	req = lastRequest(t, server, nil)
+	if req.Method != "DELETE" || req.RequestURI != "/suite-api/api/resources/"+id+"?" {
+		t.Fatalf("unexpected request: %s %s", req.Method, req.RequestURI)
+	}
+}
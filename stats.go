@@ -0,0 +1,214 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// StatsBatchSize is the maximum number of samples the client
+// sends to a server in a single request.
+const StatsBatchSize = 1000
+
+// statKeyRegex matches metric and property keys, e.g. "cpu|usage_average"
+// or "app:web01|latency_ms". The segments of a key are separated by "|".
+var statKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_\-.:]+(\|[A-Za-z0-9_\-.:]+)*$`)
+
+// StatSample is a timestamped value of a metric.
+type StatSample struct {
+	// The key of the metric, e.g. "app|latency_ms".
+	Key string `json:"key,omitempty"`
+	// The time of the sample. When zero, the current time is used.
+	Time time.Time `json:"time,omitempty"`
+	// The value of the sample.
+	Value float64 `json:"value"`
+}
+
+// PropertySample is a timestamped value of a property.
+type PropertySample struct {
+	// The key of the property, e.g. "app|version".
+	Key string `json:"key,omitempty"`
+	// The time of the sample. When zero, the current time is used.
+	Time time.Time `json:"time,omitempty"`
+	// The value of the sample.
+	Value string `json:"value"`
+}
+
+// statContent is the wire format of the samples of a single key.
+type statContent struct {
+	Key        string    `json:"statKey"`
+	Timestamps []int64   `json:"timestamps"`
+	Data       []float64 `json:"data,omitempty"`
+	Values     []string  `json:"values,omitempty"`
+}
+
+// ValidateStatKey returns an error when the key is not a valid
+// metric or property key.
+func ValidateStatKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("empty stat key")
+	}
+	if !statKeyRegex.MatchString(key) {
+		return fmt.Errorf("invalid stat key: %s", key)
+	}
+	return nil
+}
+
+// StatsBatchError is the error of pushing samples in batches. The batches
+// before the failed one were accepted by the server, i.e. the samples
+// starting at index Batch * StatsBatchSize are to be pushed again.
+type StatsBatchError struct {
+	// The kind of the samples, i.e. stats or properties.
+	Kind string
+	// Identifier of the resource the samples were pushed to.
+	ResourceID string
+	// The index of the failed batch, and the number of batches.
+	Batch   int
+	Batches int
+	// The error of the failed batch.
+	Err error
+}
+
+// Error returns the failed batch, the accepted ones, and the error.
+func (e *StatsBatchError) Error() string {
+	accepted := "none accepted"
+	if e.Batch > 0 {
+		accepted = fmt.Sprintf("batches 0-%d accepted", e.Batch-1)
+	}
+	return fmt.Sprintf("failed to add %s to resource %s: batch %d of %d failed, %s: %s",
+		e.Kind, e.ResourceID, e.Batch, e.Batches, accepted, e.Err)
+}
+
+// Unwrap returns the error of the failed batch.
+func (e *StatsBatchError) Unwrap() error {
+	return e.Err
+}
+
+// statEntry is a metric or property sample.
+type statEntry struct {
+	key   string
+	time  time.Time
+	data  float64
+	value string
+}
+
+// AddStats pushes metric samples to the resource with the provided
+// identifier. The samples are grouped by key and sent in batches. When a
+// batch fails, the returned StatsBatchError names the accepted batches.
+func (c *Client) AddStats(resourceID string, samples []*StatSample) error {
+	if len(samples) == 0 {
+		return fmt.Errorf("no stat samples")
+	}
+	entries := []*statEntry{}
+	for i, sample := range samples {
+		if sample == nil {
+			return fmt.Errorf("nil sample at index %d", i)
+		}
+		entries = append(entries, &statEntry{key: sample.Key, time: sample.Time, data: sample.Value})
+	}
+	return c.addStatEntries(resourceID, "stats", entries)
+}
+
+// AddProperties pushes property samples to the resource with the provided
+// identifier. The samples are grouped by key and sent in batches. When a
+// batch fails, the returned StatsBatchError names the accepted batches.
+func (c *Client) AddProperties(resourceID string, samples []*PropertySample) error {
+	if len(samples) == 0 {
+		return fmt.Errorf("no property samples")
+	}
+	entries := []*statEntry{}
+	for i, sample := range samples {
+		if sample == nil {
+			return fmt.Errorf("nil sample at index %d", i)
+		}
+		entries = append(entries, &statEntry{key: sample.Key, time: sample.Time, value: sample.Value})
+	}
+	return c.addStatEntries(resourceID, "properties", entries)
+}
+
+// addStatEntries sends the samples of the provided kind, i.e. stats or
+// properties, to the resource in batches of StatsBatchSize samples.
+func (c *Client) addStatEntries(resourceID, kind string, entries []*statEntry) error {
+	if resourceID == "" {
+		return fmt.Errorf("empty resource id")
+	}
+	for _, entry := range entries {
+		if err := ValidateStatKey(entry.key); err != nil {
+			return err
+		}
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	contentKey := "stat-content"
+	if kind == "properties" {
+		contentKey = "property-content"
+	}
+	now := time.Now().UTC()
+	batches := (len(entries) + StatsBatchSize - 1) / StatsBatchSize
+	for batch := 0; batch < batches; batch++ {
+		i := batch * StatsBatchSize
+		j := i + StatsBatchSize
+		if j > len(entries) {
+			j = len(entries)
+		}
+		contents := make(map[string]*statContent)
+		for _, entry := range entries[i:j] {
+			content, exists := contents[entry.key]
+			if !exists {
+				content = &statContent{Key: entry.key}
+				contents[entry.key] = content
+			}
+			ts := entry.time
+			if ts.IsZero() {
+				ts = now
+			}
+			content.Timestamps = append(content.Timestamps, timeToEpochMillis(ts))
+			if kind == "properties" {
+				content.Values = append(content.Values, entry.value)
+			} else {
+				content.Data = append(content.Data, entry.data)
+			}
+		}
+		payload := map[string][]*statContent{
+			contentKey: sortStatContents(contents),
+		}
+		params := make(map[string]string)
+		if _, err := c.requestWithPayload("POST", "resources/"+resourceID+"/"+kind, params, payload); err != nil {
+			return &StatsBatchError{
+				Kind:       kind,
+				ResourceID: resourceID,
+				Batch:      batch,
+				Batches:    batches,
+				Err:        err,
+			}
+		}
+	}
+	return nil
+}
+
+func sortStatContents(m map[string]*statContent) []*statContent {
+	contents := []*statContent{}
+	for _, content := range m {
+		contents = append(contents, content)
+	}
+	sort.Slice(contents, func(i, j int) bool {
+		return contents[i].Key < contents[j].Key
+	})
+	return contents
+}
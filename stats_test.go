@@ -0,0 +1,192 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	. "github.com/greenpau/go-vrop/internal/server"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddStats(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/resources/r1/stats": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "POST"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	// The samples of two keys, interleaved, spill over a single batch.
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := []*StatSample{}
+	for i := 0; i < StatsBatchSize+2; i++ {
+		samples = append(samples, &StatSample{
+			Key:   fmt.Sprintf("app|metric_%d", i%2),
+			Time:  ts.Add(time.Duration(i) * time.Second),
+			Value: float64(i),
+		})
+	}
+	if err := cli.AddStats("r1", samples); err != nil {
+		t.Fatalf("failed adding stats: %s", err)
+	}
+
+	payloads := []map[string][]*statContent{}
+	for _, req := range server.Requests() {
+		if !strings.HasPrefix(req.RequestURI, "/suite-api/api/resources/r1/stats") {
+			continue
+		}
+		payload := map[string][]*statContent{}
+		if err := json.Unmarshal(req.Body, &payload); err != nil {
+			t.Fatalf("failed unpacking request payload: %s", err)
+		}
+		payloads = append(payloads, payload)
+	}
+	if len(payloads) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(payloads))
+	}
+
+	// The first batch groups the samples by key.
+	contents := payloads[0]["stat-content"]
+	if len(contents) != 2 {
+		t.Fatalf("expected 2 keys in the first batch, got %d", len(contents))
+	}
+	for i, content := range contents {
+		key := fmt.Sprintf("app|metric_%d", i)
+		if content.Key != key {
+			t.Fatalf("expected key %s, got %s", key, content.Key)
+		}
+		if len(content.Timestamps) != StatsBatchSize/2 || len(content.Data) != StatsBatchSize/2 {
+			t.Fatalf("key %s: expected %d samples, got %d timestamps and %d values",
+				key, StatsBatchSize/2, len(content.Timestamps), len(content.Data))
+		}
+		if content.Data[1] != float64(i+2) || content.Timestamps[1] != timeToEpochMillis(ts.Add(time.Duration(i+2)*time.Second)) {
+			t.Fatalf("key %s: unexpected sample: %d %f", key, content.Timestamps[1], content.Data[1])
+		}
+	}
+
+	// The second batch holds the samples past the batch size.
+	want := []*statContent{
+		&statContent{
+			Key:        "app|metric_0",
+			Timestamps: []int64{timeToEpochMillis(ts.Add(StatsBatchSize * time.Second))},
+			Data:       []float64{StatsBatchSize},
+		},
+		&statContent{
+			Key:        "app|metric_1",
+			Timestamps: []int64{timeToEpochMillis(ts.Add((StatsBatchSize + 1) * time.Second))},
+			Data:       []float64{StatsBatchSize + 1},
+		},
+	}
+	if !reflect.DeepEqual(payloads[1]["stat-content"], want) {
+		b, _ := json.Marshal(payloads[1])
+		t.Fatalf("unexpected second batch: %s", b)
+	}
+}
+
+func TestAddPropertiesGroupsByKey(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/resources/r1/properties": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "POST"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := []*PropertySample{
+		&PropertySample{Key: "app|version", Time: ts, Value: "1.0"},
+		&PropertySample{Key: "app|owner", Time: ts, Value: "web"},
+		&PropertySample{Key: "app|version", Time: ts.Add(time.Minute), Value: "1.1"},
+	}
+	if err := cli.AddProperties("r1", samples); err != nil {
+		t.Fatalf("failed adding properties: %s", err)
+	}
+
+	var body []byte
+	for _, req := range server.Requests() {
+		if strings.HasPrefix(req.RequestURI, "/suite-api/api/resources/r1/properties") {
+			body = req.Body
+		}
+	}
+	payload := map[string][]*statContent{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed unpacking request payload: %s", err)
+	}
+	want := []*statContent{
+		&statContent{
+			Key:        "app|owner",
+			Timestamps: []int64{timeToEpochMillis(ts)},
+			Values:     []string{"web"},
+		},
+		&statContent{
+			Key:        "app|version",
+			Timestamps: []int64{timeToEpochMillis(ts), timeToEpochMillis(ts.Add(time.Minute))},
+			Values:     []string{"1.0", "1.1"},
+		},
+	}
+	if !reflect.DeepEqual(payload["property-content"], want) {
+		t.Fatalf("unexpected payload: %s", body)
+	}
+}
+
+func TestAddStatsNilSample(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{})
+	defer server.Close()
+	defer cli.Close()
+
+	err := cli.AddStats("r1", []*StatSample{&StatSample{Key: "app|latency_ms"}, nil})
+	if err == nil || err.Error() != "nil sample at index 1" {
+		t.Fatalf("expected nil sample error, got: %v", err)
+	}
+	err = cli.AddProperties("r1", []*PropertySample{nil})
+	if err == nil || err.Error() != "nil sample at index 0" {
+		t.Fatalf("expected nil sample error, got: %v", err)
+	}
+	if len(server.Requests()) != 0 {
+		t.Fatalf("expected no requests, got %d", len(server.Requests()))
+	}
+}
+
+func TestAddStatsBatchFailure(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/resources/r1/properties": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "POST", Limit: 2},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	samples := []*PropertySample{}
+	for i := 0; i < 3*StatsBatchSize; i++ {
+		samples = append(samples, &PropertySample{Key: "app|version", Value: "1.0"})
+	}
+	err := cli.AddProperties("r1", samples)
+	var batchErr *StatsBatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected StatsBatchError, got: %v", err)
+	}
+	if batchErr.ResourceID != "r1" || batchErr.Batch != 2 || batchErr.Batches != 3 {
+		t.Fatalf("unexpected batch error: %+v", batchErr)
+	}
+	if !strings.HasPrefix(err.Error(), "failed to add properties to resource r1: batch 2 of 3 failed, batches 0-1 accepted: ") {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
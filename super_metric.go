@@ -0,0 +1,286 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// SuperMetric is a metric computed by a formula from other metrics.
+type SuperMetric struct {
+	// Identifier of the SuperMetric, assigned by the server.
+	ID string `json:"id,omitempty"`
+	// Name and description of the SuperMetric.
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// The formula of the SuperMetric, e.g.
+	// avg(${adaptertype=VMWARE, objecttype=VirtualMachine, attribute=cpu|usage_average, depth=1}).
+	Formula string `json:"formula"`
+	// The unit of the SuperMetric, e.g. percent.
+	UnitID string `json:"unitId,omitempty"`
+	// The time the SuperMetric was last modified and the user modifying it.
+	ModificationTime Timestamp `json:"modificationTime"`
+	ModifiedBy       string    `json:"modifiedBy,omitempty"`
+}
+
+// ResourceKindRef is a reference to a resource kind of an adapter kind.
+type ResourceKindRef struct {
+	AdapterKind  string `json:"adapterKind"`
+	ResourceKind string `json:"resourceKind"`
+}
+
+// SuperMetricsResponse is a response with super metrics.
+type SuperMetricsResponse struct {
+	Page         *PageInfo      `json:"pageInfo,omitempty"`
+	Links        []*Link        `json:"links,omitempty"`
+	SuperMetrics []*SuperMetric `json:"superMetrics,omitempty"`
+}
+
+// GetSuperMetrics returns a list of super metrics.
+func (c *Client) GetSuperMetrics() ([]*SuperMetric, error) {
+	metrics := []*SuperMetric{}
+	if err := c.authenticate(); err != nil {
+		return metrics, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := make(map[string]string)
+		params["page"] = strconv.Itoa(pageOffset)
+		params["pageSize"] = strconv.Itoa(pageSize)
+		b, err := c.request("GET", "supermetrics", params)
+		if err != nil {
+			return metrics, err
+		}
+
+		resp := &SuperMetricsResponse{}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return metrics, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+
+		metrics = append(metrics, resp.SuperMetrics...)
+
+		if len(resp.SuperMetrics) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return metrics, nil
+}
+
+// GetSuperMetric returns the super metric with the provided identifier.
+func (c *Client) GetSuperMetric(id string) (*SuperMetric, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty super metric id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.request("GET", "supermetrics/"+id, params)
+	if err != nil {
+		return nil, err
+	}
+	m := &SuperMetric{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return m, nil
+}
+
+// CreateSuperMetric creates the super metric and returns it with the
+// identifier assigned by the server.
+func (c *Client) CreateSuperMetric(m *SuperMetric) (*SuperMetric, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	payload := *m
+	payload.ID = ""
+	params := make(map[string]string)
+	b, err := c.requestWithPayload("POST", "supermetrics", params, &payload)
+	if err != nil {
+		return nil, err
+	}
+	created := &SuperMetric{}
+	if err := json.Unmarshal(b, created); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return created, nil
+}
+
+// UpdateSuperMetric updates the super metric.
+func (c *Client) UpdateSuperMetric(m *SuperMetric) (*SuperMetric, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	if m.ID == "" {
+		return nil, fmt.Errorf("empty super metric id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.requestWithPayload("PUT", "supermetrics", params, m)
+	if err != nil {
+		return nil, err
+	}
+	updated := &SuperMetric{}
+	if err := json.Unmarshal(b, updated); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return updated, nil
+}
+
+// DeleteSuperMetric deletes the super metric with the provided identifier.
+func (c *Client) DeleteSuperMetric(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty super metric id")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	if _, err := c.request("DELETE", "supermetrics/"+id, params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AssignSuperMetric assigns the super metric with the provided identifier
+// to the resource kinds, i.e. the super metric is computed for the
+// resources of the kinds. The assignment is a PUT of the resource kinds to
+// supermetrics/{id}/assign.
+func (c *Client) AssignSuperMetric(id string, kinds []*ResourceKindRef) error {
+	if id == "" {
+		return fmt.Errorf("empty super metric id")
+	}
+	if len(kinds) == 0 {
+		return fmt.Errorf("no resource kinds to assign super metric %s", id)
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	payload := map[string]interface{}{
+		"resourceKindKeys": kinds,
+	}
+	if _, err := c.requestWithPayload("PUT", "supermetrics/"+id+"/assign", params, payload); err != nil {
+		return fmt.Errorf("failed to assign super metric %s: %s", id, err)
+	}
+	return nil
+}
+
+// EnableSuperMetric enables the super metric with the provided identifier
+// for the resource kinds in the policies with the provided identifiers.
+// Same as AssignSuperMetric, it is a PUT to supermetrics/{id}/assign, with
+// the policies added to the payload.
+func (c *Client) EnableSuperMetric(id string, policyIDs []string, kinds []*ResourceKindRef) error {
+	if id == "" {
+		return fmt.Errorf("empty super metric id")
+	}
+	if len(policyIDs) == 0 {
+		return fmt.Errorf("no policy ids to enable super metric %s", id)
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	payload := map[string]interface{}{
+		"policyIds":        policyIDs,
+		"resourceKindKeys": kinds,
+	}
+	if _, err := c.requestWithPayload("PUT", "supermetrics/"+id+"/assign", params, payload); err != nil {
+		return fmt.Errorf("failed to enable super metric %s in policies: %s", id, err)
+	}
+	return nil
+}
+
+// ApplySuperMetric creates or updates the super metric so that the server
+// has the same name, formula, description, and unit. The existing super
+// metric is looked up by identifier, then by name. It returns the applied
+// super metric and whether it was changed.
+func (c *Client) ApplySuperMetric(m *SuperMetric, existing []*SuperMetric) (*SuperMetric, bool, error) {
+	var current *SuperMetric
+	for _, item := range existing {
+		if m.ID != "" && item.ID == m.ID {
+			current = item
+			break
+		}
+	}
+	if current == nil {
+		for _, item := range existing {
+			if item.Name == m.Name {
+				current = item
+				break
+			}
+		}
+	}
+	if current == nil {
+		created, err := c.CreateSuperMetric(m)
+		if err != nil {
+			return nil, false, err
+		}
+		return created, true, nil
+	}
+	if current.Equal(m) {
+		return current, false, nil
+	}
+	desired := *m
+	desired.ID = current.ID
+	updated, err := c.UpdateSuperMetric(&desired)
+	if err != nil {
+		return nil, false, err
+	}
+	return updated, true, nil
+}
+
+// Equal returns true when the super metrics have the same name, formula,
+// description, and unit.
+func (m *SuperMetric) Equal(other *SuperMetric) bool {
+	return m.Name == other.Name &&
+		m.Formula == other.Formula &&
+		m.Description == other.Description &&
+		m.UnitID == other.UnitID
+}
+
+// ToJSONString serializes SuperMetric to a string.
+func (m *SuperMetric) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
+
+func (m *SuperMetric) validate() error {
+	if m == nil {
+		return fmt.Errorf("nil super metric")
+	}
+	if m.Name == "" {
+		return fmt.Errorf("super metric name is empty")
+	}
+	if m.Formula == "" {
+		return fmt.Errorf("super metric %s has empty formula", m.Name)
+	}
+	return nil
+}
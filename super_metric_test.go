@@ -0,0 +1,191 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	. "github.com/greenpau/go-vrop/internal/server"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testSuperMetricID = "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"
+
+func newSuperMetricMockClient(t *testing.T) (*Client, *MockTestServer) {
+	return newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/supermetrics": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "super_metrics.json"},
+			&MockTestEndpoint{Method: "POST", FileName: "super_metric.json"},
+			&MockTestEndpoint{Method: "PUT", FileName: "super_metric.json"},
+		},
+		"/suite-api/api/supermetrics/" + testSuperMetricID: []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "super_metric.json"},
+			&MockTestEndpoint{Method: "DELETE"},
+		},
+		"/suite-api/api/supermetrics/" + testSuperMetricID + "/assign": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "PUT"},
+		},
+	})
+}
+
+func TestSuperMetricLifecycle(t *testing.T) {
+	cli, server := newSuperMetricMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	metrics, err := cli.GetSuperMetrics()
+	if err != nil {
+		t.Fatalf("failed getting super metrics: %s", err)
+	}
+	if len(metrics) != 1 || metrics[0].ID != testSuperMetricID || metrics[0].UnitID != "percent" {
+		t.Fatalf("unexpected super metrics: %+v", metrics)
+	}
+
+	m := &SuperMetric{
+		Name:        "Cluster VM CPU Usage",
+		Description: "Average CPU usage of the virtual machines in a cluster",
+		Formula:     "avg(${adaptertype=VMWARE, objecttype=VirtualMachine, attribute=cpu|usage_average, depth=1})",
+		UnitID:      "percent",
+	}
+	for _, invalid := range []*SuperMetric{nil, &SuperMetric{Formula: m.Formula}, &SuperMetric{Name: m.Name}} {
+		if _, err := cli.CreateSuperMetric(invalid); err == nil {
+			t.Fatalf("expected error for invalid super metric %+v, got none", invalid)
+		}
+	}
+
+	// The existing super metric is unchanged.
+	applied, changed, err := cli.ApplySuperMetric(m, metrics)
+	if err != nil {
+		t.Fatalf("failed applying super metric: %s", err)
+	}
+	if changed || applied.ID != testSuperMetricID {
+		t.Fatalf("unexpected applied super metric: %+v, changed: %t", applied, changed)
+	}
+
+	// The super metric is created when not found.
+	if _, changed, err = cli.ApplySuperMetric(m, nil); err != nil || !changed {
+		t.Fatalf("failed creating super metric: %v, changed: %t", err, changed)
+	}
+	payload := map[string]interface{}{}
+	req := lastRequest(t, server, &payload)
+	if req.Method != "POST" || !strings.HasPrefix(req.RequestURI, "/suite-api/api/supermetrics?") {
+		t.Fatalf("unexpected request: %s %s", req.Method, req.RequestURI)
+	}
+	if _, exists := payload["id"]; exists || payload["formula"] != m.Formula || payload["unitId"] != "percent" {
+		t.Fatalf("unexpected create payload: %s", req.Body)
+	}
+
+	// The super metric is updated when its formula differs.
+	m.Formula = "max(${adaptertype=VMWARE, objecttype=VirtualMachine, attribute=cpu|usage_average, depth=1})"
+	if _, changed, err = cli.ApplySuperMetric(m, metrics); err != nil || !changed {
+		t.Fatalf("failed updating super metric: %v, changed: %t", err, changed)
+	}
+	updated := &SuperMetric{}
+	req = lastRequest(t, server, updated)
+	if req.Method != "PUT" || !strings.HasPrefix(req.RequestURI, "/suite-api/api/supermetrics?") {
+		t.Fatalf("unexpected request: %s %s", req.Method, req.RequestURI)
+	}
+	if updated.ID != testSuperMetricID || updated.Formula != m.Formula {
+		t.Fatalf("unexpected update payload: %s", req.Body)
+	}
+
+	if err := cli.DeleteSuperMetric(testSuperMetricID); err != nil {
+		t.Fatalf("failed deleting super metric: %s", err)
+	}
+	req = lastRequest(t, server, nil)
+	if req.Method != "DELETE" || !strings.HasPrefix(req.RequestURI, "/suite-api/api/supermetrics/"+testSuperMetricID) {
+		t.Fatalf("unexpected request: %s %s", req.Method, req.RequestURI)
+	}
+}
+
+func TestAssignSuperMetric(t *testing.T) {
+	cli, server := newSuperMetricMockClient(t)
+	defer server.Close()
+	defer cli.Close()
+
+	kinds := []*ResourceKindRef{
+		&ResourceKindRef{AdapterKind: "VMWARE", ResourceKind: "ClusterComputeResource"},
+	}
+	testcases := []struct {
+		name      string
+		call      func() error
+		shouldErr bool
+		payload   map[string]interface{}
+	}{
+		{
+			name: "assign super metric to resource kinds",
+			call: func() error { return cli.AssignSuperMetric(testSuperMetricID, kinds) },
+			payload: map[string]interface{}{
+				"resourceKindKeys": []interface{}{
+					map[string]interface{}{"adapterKind": "VMWARE", "resourceKind": "ClusterComputeResource"},
+				},
+			},
+		},
+		{
+			name: "enable super metric in policies",
+			call: func() error {
+				return cli.EnableSuperMetric(testSuperMetricID, []string{"policy-1", "policy-2"}, kinds)
+			},
+			payload: map[string]interface{}{
+				"policyIds": []interface{}{"policy-1", "policy-2"},
+				"resourceKindKeys": []interface{}{
+					map[string]interface{}{"adapterKind": "VMWARE", "resourceKind": "ClusterComputeResource"},
+				},
+			},
+		},
+		{
+			name:      "assign super metric without resource kinds",
+			call:      func() error { return cli.AssignSuperMetric(testSuperMetricID, nil) },
+			shouldErr: true,
+		},
+		{
+			name:      "enable super metric without policies",
+			call:      func() error { return cli.EnableSuperMetric(testSuperMetricID, nil, kinds) },
+			shouldErr: true,
+		},
+		{
+			name:      "assign super metric without id",
+			call:      func() error { return cli.AssignSuperMetric("", kinds) },
+			shouldErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			count := len(server.Requests())
+			err := tc.call()
+			if tc.shouldErr {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				if len(server.Requests()) != count {
+					t.Fatalf("unexpected request sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			payload := map[string]interface{}{}
+			req := lastRequest(t, server, &payload)
+			if req.Method != "PUT" || req.RequestURI != "/suite-api/api/supermetrics/"+testSuperMetricID+"/assign?" {
+				t.Fatalf("unexpected request: %s %s", req.Method, req.RequestURI)
+			}
+			if !reflect.DeepEqual(payload, tc.payload) {
+				t.Fatalf("unexpected payload:\ngot:  %v\nwant: %v", payload, tc.payload)
+			}
+		})
+	}
+}
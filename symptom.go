@@ -0,0 +1,105 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Symptom is a symptom triggered on a resource.
+type Symptom struct {
+	// Identifier of the Symptom.
+	ID string `json:"id,omitempty"`
+	// Identifier of the Resource the Symptom was triggered on.
+	ResourceID string `json:"resourceId,omitempty"`
+	// Identifier of the definition of the Symptom.
+	DefinitionID string `json:"symptomDefinitionId,omitempty"`
+	// The criticality of the Symptom, e.g. CRITICAL, IMMEDIATE, WARNING,
+	// INFORMATION.
+	Criticality string `json:"symptomCriticality,omitempty"`
+	// The metric or property key that triggered the Symptom.
+	StatKey string `json:"statKey,omitempty"`
+	// A human readable message describing the Symptom.
+	Message string `json:"message,omitempty"`
+	// The time the Symptom was triggered, updated, and canceled.
+	StartTime  Timestamp `json:"startTimeUTC"`
+	UpdateTime Timestamp `json:"updateTimeUTC"`
+	CancelTime Timestamp `json:"cancelTimeUTC"`
+	// Set of useful links related to the current object.
+	Links []*Link `json:"links,omitempty"`
+}
+
+// SymptomsResponse is a response with symptoms.
+type SymptomsResponse struct {
+	Page     *PageInfo  `json:"pageInfo,omitempty"`
+	Links    []*Link    `json:"links,omitempty"`
+	Symptoms []*Symptom `json:"symptom,omitempty"`
+}
+
+// GetSymptoms returns a list of symptoms triggered on the resources
+// with the provided identifiers. When activeOnly is true, the canceled
+// symptoms are omitted.
+func (c *Client) GetSymptoms(resourceIDs []string, activeOnly bool) ([]*Symptom, error) {
+	symptoms := []*Symptom{}
+	if len(resourceIDs) == 0 {
+		return symptoms, fmt.Errorf("no resource ids")
+	}
+	if err := c.authenticate(); err != nil {
+		return symptoms, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := url.Values{}
+		for _, id := range resourceIDs {
+			params.Add("resourceId", id)
+		}
+		params.Set("activeOnly", strconv.FormatBool(activeOnly))
+		params.Set("page", strconv.Itoa(pageOffset))
+		params.Set("pageSize", strconv.Itoa(pageSize))
+		b, err := c.requestWithValues("GET", "symptoms", params, nil)
+		if err != nil {
+			return symptoms, err
+		}
+
+		resp := &SymptomsResponse{}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return symptoms, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+
+		symptoms = append(symptoms, resp.Symptoms...)
+
+		if len(resp.Symptoms) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return symptoms, nil
+}
+
+// ToJSONString serializes Symptom to a string.
+func (s *Symptom) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
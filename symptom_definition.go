@@ -0,0 +1,347 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// SymptomDefinition is the definition of a symptom.
+type SymptomDefinition struct {
+	// Identifier of the SymptomDefinition, assigned by the server.
+	ID string `json:"id,omitempty"`
+	// Name of the SymptomDefinition.
+	Name string `json:"name"`
+	// Adapter Kind and Resource Kind the SymptomDefinition applies to.
+	AdapterKindKey  string `json:"adapterKindKey"`
+	ResourceKindKey string `json:"resourceKindKey"`
+	// The number of collection cycles the condition must be met
+	// before the symptom is triggered, and not met before it is canceled.
+	WaitCycles   int `json:"waitCycles,omitempty"`
+	CancelCycles int `json:"cancelCycles,omitempty"`
+	// The state of the SymptomDefinition.
+	State *SymptomState `json:"state,omitempty"`
+	// Extra holds the values of the keys not supported by this package,
+	// so that they are sent back on update.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// SymptomState is the state of a symptom definition, i.e. the severity
+// of the symptom triggered when its condition is met.
+type SymptomState struct {
+	// The severity of the symptom, e.g. CRITICAL, IMMEDIATE, WARNING,
+	// INFORMATION, AUTO.
+	Severity string `json:"severity,omitempty"`
+	// The condition triggering the symptom.
+	Condition *SymptomCondition `json:"condition,omitempty"`
+	// Extra holds the values of the keys not supported by this package.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// SymptomCondition is the condition of a symptom.
+type SymptomCondition struct {
+	// The type of the condition, e.g. CONDITION_HT (hard threshold),
+	// CONDITION_DT (dynamic threshold), CONDITION_PROPERTY_STRING,
+	// CONDITION_MESSAGE_EVENT, CONDITION_FAULT.
+	Type string `json:"type,omitempty"`
+	// The metric or property key the condition evaluates.
+	Key string `json:"key,omitempty"`
+	// The operator of the condition, e.g. GT, LT, EQ, NOT_EQ, CONTAINS.
+	Operator string `json:"operator,omitempty"`
+	// The value the metric or property is compared with.
+	Value string `json:"value,omitempty"`
+	// The type of the value, i.e. NUMERIC or STRING.
+	ValueType string `json:"valueType,omitempty"`
+	// Whether the condition applies to metric instances.
+	Instanced bool `json:"instanced"`
+	// The type of the threshold, e.g. STATIC.
+	ThresholdType string `json:"thresholdType,omitempty"`
+	// The identifier of the fault and the event message, if applicable.
+	FaultKey     string `json:"faultKey,omitempty"`
+	EventType    string `json:"eventType,omitempty"`
+	EventSubType string `json:"eventSubType,omitempty"`
+	Message      string `json:"message,omitempty"`
+	// Extra holds the values of the keys not supported by this package.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// SymptomDefinitionsResponse is a response with symptom definitions.
+type SymptomDefinitionsResponse struct {
+	Page               *PageInfo            `json:"pageInfo,omitempty"`
+	Links              []*Link              `json:"links,omitempty"`
+	SymptomDefinitions []*SymptomDefinition `json:"symptomDefinitions,omitempty"`
+}
+
+// GetSymptomDefinitions returns a list of symptom definitions. The options
+// filter the definitions by adapter kind (adapter_kind) and resource
+// kind (resource_kind).
+func (c *Client) GetSymptomDefinitions(opts map[string]interface{}) ([]*SymptomDefinition, error) {
+	definitions := []*SymptomDefinition{}
+	filter, err := definitionFilter(opts)
+	if err != nil {
+		return definitions, err
+	}
+	if err := c.authenticate(); err != nil {
+		return definitions, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := make(map[string]string)
+		for k, v := range filter {
+			params[k] = v
+		}
+		params["page"] = strconv.Itoa(pageOffset)
+		params["pageSize"] = strconv.Itoa(pageSize)
+		b, err := c.request("GET", "symptomdefinitions", params)
+		if err != nil {
+			return definitions, err
+		}
+
+		resp := &SymptomDefinitionsResponse{}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return definitions, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+
+		definitions = append(definitions, resp.SymptomDefinitions...)
+
+		if len(resp.SymptomDefinitions) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return definitions, nil
+}
+
+// GetSymptomDefinition returns the symptom definition with the provided identifier.
+func (c *Client) GetSymptomDefinition(id string) (*SymptomDefinition, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty symptom definition id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.request("GET", "symptomdefinitions/"+id, params)
+	if err != nil {
+		return nil, err
+	}
+	d := &SymptomDefinition{}
+	if err := json.Unmarshal(b, d); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return d, nil
+}
+
+// CreateSymptomDefinition creates the symptom definition and returns it
+// with the identifier assigned by the server.
+func (c *Client) CreateSymptomDefinition(d *SymptomDefinition) (*SymptomDefinition, error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	payload := *d
+	payload.ID = ""
+	params := make(map[string]string)
+	b, err := c.requestWithPayload("POST", "symptomdefinitions", params, &payload)
+	if err != nil {
+		return nil, err
+	}
+	created := &SymptomDefinition{}
+	if err := json.Unmarshal(b, created); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return created, nil
+}
+
+// UpdateSymptomDefinition updates the symptom definition.
+func (c *Client) UpdateSymptomDefinition(d *SymptomDefinition) (*SymptomDefinition, error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+	if d.ID == "" {
+		return nil, fmt.Errorf("empty symptom definition id")
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.requestWithPayload("PUT", "symptomdefinitions", params, d)
+	if err != nil {
+		return nil, err
+	}
+	updated := &SymptomDefinition{}
+	if err := json.Unmarshal(b, updated); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	return updated, nil
+}
+
+// DeleteSymptomDefinition deletes the symptom definition with the provided identifier.
+func (c *Client) DeleteSymptomDefinition(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty symptom definition id")
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	if _, err := c.request("DELETE", "symptomdefinitions/"+id, params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ApplySymptomDefinition creates or updates the symptom definition so that
+// the server has the same definition. The existing definition is looked up
+// by identifier, then by name. The definition is not updated when it already
+// matches. The fields of the existing definition not supported by this
+// package are kept on update. It returns the applied definition and whether
+// it was changed.
+func (c *Client) ApplySymptomDefinition(d *SymptomDefinition, existing []*SymptomDefinition) (*SymptomDefinition, bool, error) {
+	items := []definition{}
+	for _, item := range existing {
+		items = append(items, item)
+	}
+	applied, changed, err := applyDefinition(d, items,
+		func(x definition) (definition, error) {
+			return c.CreateSymptomDefinition(x.(*SymptomDefinition))
+		},
+		func(x definition) (definition, error) {
+			return c.UpdateSymptomDefinition(x.(*SymptomDefinition))
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	return applied.(*SymptomDefinition), changed, nil
+}
+
+func (d *SymptomDefinition) identity() definitionIdentity {
+	return definitionIdentity{
+		ID:              d.ID,
+		Name:            d.Name,
+		AdapterKindKey:  d.AdapterKindKey,
+		ResourceKindKey: d.ResourceKindKey,
+	}
+}
+
+func (d *SymptomDefinition) desired(current definition) (definition, error) {
+	cur := current.(*SymptomDefinition)
+	desired := &SymptomDefinition{}
+	if err := jsonCopy(d, desired); err != nil {
+		return nil, err
+	}
+	desired.ID = cur.ID
+	desired.Extra = inheritExtra(desired.Extra, cur.Extra)
+	if desired.State != nil && cur.State != nil {
+		desired.State.Extra = inheritExtra(desired.State.Extra, cur.State.Extra)
+		if desired.State.Condition != nil && cur.State.Condition != nil {
+			desired.State.Condition.Extra = inheritExtra(desired.State.Condition.Extra, cur.State.Condition.Extra)
+		}
+	}
+	return desired, nil
+}
+
+// Equal returns true when the symptom definitions have the same JSON representation.
+func (d *SymptomDefinition) Equal(other *SymptomDefinition) bool {
+	return jsonEqual(d, other)
+}
+
+// UnmarshalJSON unpacks SymptomDefinition. The keys not supported by this
+// package are preserved in Extra.
+func (d *SymptomDefinition) UnmarshalJSON(b []byte) error {
+	type alias SymptomDefinition
+	extra, err := unpackWithExtra(b, (*alias)(d))
+	if err != nil {
+		return fmt.Errorf("failed to unpack SymptomDefinition: %s", err)
+	}
+	d.Extra = extra
+	return nil
+}
+
+// MarshalJSON packs SymptomDefinition with the keys preserved in Extra.
+func (d SymptomDefinition) MarshalJSON() ([]byte, error) {
+	type alias SymptomDefinition
+	return packWithExtra(alias(d), d.Extra)
+}
+
+// UnmarshalJSON unpacks SymptomState. The keys not supported by this
+// package are preserved in Extra.
+func (s *SymptomState) UnmarshalJSON(b []byte) error {
+	type alias SymptomState
+	extra, err := unpackWithExtra(b, (*alias)(s))
+	if err != nil {
+		return fmt.Errorf("failed to unpack SymptomState: %s", err)
+	}
+	s.Extra = extra
+	return nil
+}
+
+// MarshalJSON packs SymptomState with the keys preserved in Extra.
+func (s SymptomState) MarshalJSON() ([]byte, error) {
+	type alias SymptomState
+	return packWithExtra(alias(s), s.Extra)
+}
+
+// UnmarshalJSON unpacks SymptomCondition. The keys not supported by this
+// package are preserved in Extra.
+func (s *SymptomCondition) UnmarshalJSON(b []byte) error {
+	type alias SymptomCondition
+	extra, err := unpackWithExtra(b, (*alias)(s))
+	if err != nil {
+		return fmt.Errorf("failed to unpack SymptomCondition: %s", err)
+	}
+	s.Extra = extra
+	return nil
+}
+
+// MarshalJSON packs SymptomCondition with the keys preserved in Extra.
+func (s SymptomCondition) MarshalJSON() ([]byte, error) {
+	type alias SymptomCondition
+	return packWithExtra(alias(s), s.Extra)
+}
+
+// ToJSONString serializes SymptomDefinition to a string.
+func (d *SymptomDefinition) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(d)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
+
+func (d *SymptomDefinition) validate() error {
+	if d == nil {
+		return fmt.Errorf("nil symptom definition")
+	}
+	if d.Name == "" {
+		return fmt.Errorf("symptom definition name is empty")
+	}
+	if d.AdapterKindKey == "" || d.ResourceKindKey == "" {
+		return fmt.Errorf("symptom definition %s has no adapter kind or resource kind", d.Name)
+	}
+	if d.State == nil {
+		return fmt.Errorf("symptom definition %s has no state", d.Name)
+	}
+	return nil
+}
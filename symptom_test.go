@@ -0,0 +1,62 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	. "github.com/greenpau/go-vrop/internal/server"
+	"net/url"
+	"testing"
+)
+
+func TestGetSymptoms(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/symptoms": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "symptoms.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	if _, err := cli.GetSymptoms(nil, true); err == nil {
+		t.Fatalf("expected error for empty resource ids, got none")
+	}
+
+	symptoms, err := cli.GetSymptoms([]string{"vm-1", "vm-2"}, true)
+	if err != nil {
+		t.Fatalf("failed getting symptoms: %s", err)
+	}
+	if len(symptoms) != 2 {
+		t.Fatalf("expected 2 symptoms, got %d", len(symptoms))
+	}
+	s := symptoms[0]
+	if s.ID != "symptom-1" || s.ResourceID != "vm-1" ||
+		s.DefinitionID != "SymptomDefinition-a1b2c3d4-0001" ||
+		s.Criticality != "CRITICAL" || s.StartTime.IsZero() {
+		t.Fatalf("unexpected symptom: %+v", s)
+	}
+
+	requests := server.Requests()
+	u, err := url.Parse(requests[len(requests)-1].RequestURI)
+	if err != nil {
+		t.Fatalf("failed parsing request uri: %s", err)
+	}
+	q := u.Query()
+	if ids := q["resourceId"]; len(ids) != 2 || ids[0] != "vm-1" || ids[1] != "vm-2" {
+		t.Fatalf("unexpected resourceId params: %v", ids)
+	}
+	if q.Get("activeOnly") != "true" || q.Get("page") != "0" || q.Get("pageSize") != "100" {
+		t.Fatalf("unexpected query: %s", u.RawQuery)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tag is a tag, i.e. a value (name) of a tag key (category).
+type Tag struct {
+	// The key of the tag, e.g. CostCenter.
+	Category string `json:"category"`
+	// The value of the tag, e.g. 1234.
+	Name string `json:"name"`
+}
+
+// TagsResponse is a response with tags.
+type TagsResponse struct {
+	Page  *PageInfo `json:"pageInfo,omitempty"`
+	Links []*Link   `json:"links,omitempty"`
+	Tags  []*Tag    `json:"tags,omitempty"`
+}
+
+// NewTag returns an instance of Tag.
+func NewTag(category, name string) *Tag {
+	return &Tag{
+		Category: category,
+		Name:     name,
+	}
+}
+
+// String returns the tag in the category:name format.
+func (t *Tag) String() string {
+	return t.Category + ":" + t.Name
+}
+
+// ParseTag parses a tag in the category:name format.
+func ParseTag(s string) (*Tag, error) {
+	arr := strings.SplitN(s, ":", 2)
+	if len(arr) != 2 {
+		return nil, fmt.Errorf("invalid tag %q, expected category:name", s)
+	}
+	t := NewTag(strings.TrimSpace(arr[0]), strings.TrimSpace(arr[1]))
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetTags returns a list of tags.
+func (c *Client) GetTags() ([]*Tag, error) {
+	tags := []*Tag{}
+	if err := c.authenticate(); err != nil {
+		return tags, err
+	}
+
+	pageOffset := 0
+	pageSize := 100
+
+	for {
+		params := make(map[string]string)
+		params["page"] = strconv.Itoa(pageOffset)
+		params["pageSize"] = strconv.Itoa(pageSize)
+		b, err := c.request("GET", "tags", params)
+		if err != nil {
+			return tags, err
+		}
+
+		resp := &TagsResponse{}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return tags, fmt.Errorf("failed unmarshalling response: %s", err)
+		}
+
+		tags = append(tags, resp.Tags...)
+
+		if len(resp.Tags) < pageSize {
+			break
+		}
+		pageOffset++
+	}
+
+	return tags, nil
+}
+
+// CreateTags creates the tags.
+func (c *Client) CreateTags(tags ...*Tag) error {
+	if err := validateTags(tags); err != nil {
+		return err
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	payload := map[string]interface{}{
+		"tags": tags,
+	}
+	if _, err := c.requestWithPayload("POST", "tags", params, payload); err != nil {
+		return fmt.Errorf("failed to create tags: %s", err)
+	}
+	return nil
+}
+
+// AssignTags assigns the tags to the resources with the provided identifiers.
+func (c *Client) AssignTags(resourceIDs []string, tags []*Tag) error {
+	return c.modifyResourceTags("assign", resourceIDs, tags)
+}
+
+// UnassignTags removes the tags from the resources with the provided identifiers.
+func (c *Client) UnassignTags(resourceIDs []string, tags []*Tag) error {
+	return c.modifyResourceTags("unassign", resourceIDs, tags)
+}
+
+func (c *Client) modifyResourceTags(action string, resourceIDs []string, tags []*Tag) error {
+	if len(resourceIDs) == 0 {
+		return fmt.Errorf("no resource ids to %s tags", action)
+	}
+	if err := validateTags(tags); err != nil {
+		return err
+	}
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	payload := map[string]interface{}{
+		"resourceIds": resourceIDs,
+		"tags":        tags,
+	}
+	if _, err := c.requestWithPayload("POST", "tags/"+action, params, payload); err != nil {
+		return fmt.Errorf("failed to %s tags: %s", action, err)
+	}
+	return nil
+}
+
+func (t *Tag) validate() error {
+	if t == nil {
+		return fmt.Errorf("nil tag")
+	}
+	if t.Category == "" {
+		return fmt.Errorf("tag category is empty")
+	}
+	if t.Name == "" {
+		return fmt.Errorf("tag %s has empty name", t.Category)
+	}
+	return nil
+}
+
+func validateTags(tags []*Tag) error {
+	if len(tags) == 0 {
+		return fmt.Errorf("no tags")
+	}
+	for _, t := range tags {
+		if err := t.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
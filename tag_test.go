@@ -0,0 +1,42 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	. "github.com/greenpau/go-vrop/internal/server"
+	"testing"
+)
+
+func TestGetTags(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/tags": []*MockTestEndpoint{
+			&MockTestEndpoint{Query: "page=0&pageSize=100", FileName: "tags_page_0.json"},
+			&MockTestEndpoint{Query: "page=1&pageSize=100", FileName: "tags_page_1.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	tags, err := cli.GetTags()
+	if err != nil {
+		t.Fatalf("failed getting tags: %s", err)
+	}
+	if len(tags) != 101 {
+		t.Fatalf("expected 101 tags across 2 pages, got %d", len(tags))
+	}
+	if tag := tags[100]; tag.Category != "CostCenter" || tag.Name != "1100" {
+		t.Fatalf("unexpected tag on the second page: %+v", tag)
+	}
+}
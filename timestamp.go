@@ -0,0 +1,76 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Timestamp is a point in time. The API represents it as the number of
+// milliseconds elapsed since Unix epoch.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp returns Timestamp for the provided time.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// MarshalJSON packs Timestamp into milliseconds since Unix epoch.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(timeToEpochMillis(t.Time))
+}
+
+// UnmarshalJSON unpacks milliseconds since Unix epoch into Timestamp.
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to unpack timestamp: %s", err)
+	}
+	switch n := v.(type) {
+	case nil:
+		t.Time = time.Time{}
+	case float64:
+		t.Time = epochMillisToTime(n)
+	default:
+		return fmt.Errorf("failed to unpack timestamp, unsupported value: %s", b)
+	}
+	return nil
+}
+
+// epochMillisToTime converts milliseconds since Unix epoch to time.
+// The zero value is converted to zero time.
+func epochMillisToTime(v float64) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	// The milliseconds are converted as integers to avoid the loss of
+	// precision of the fractional seconds.
+	msecs := int64(math.Round(v))
+	return time.Unix(msecs/1000, (msecs%1000)*int64(time.Millisecond))
+}
+
+// timeToEpochMillis converts time to milliseconds since Unix epoch.
+// The zero time is converted to zero.
+func timeToEpochMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// VCenterInstance is an instance of vCenter adapter, i.e. a vCenter Server
+// monitored by vRealize Operations Manager.
+type VCenterInstance struct {
+	InventoryItem
+	// The URL of the vCenter Server.
+	VCURL string `json:"vc_url,omitempty"`
+	// The version of the vCenter Server.
+	Version string `json:"version,omitempty"`
+}
+
+// GetVCenterInstances returns a list of VCenterInstance instances,
+// selected by the options of GetResources, e.g. name or tags.
+func (c *Client) GetVCenterInstances(opts map[string]interface{}) ([]*VCenterInstance, error) {
+	instances := []*VCenterInstance{}
+	err := c.getInventory("VMWARE", "VMwareAdapter Instance", opts, func() inventoryResource {
+		m := &VCenterInstance{}
+		instances = append(instances, m)
+		return m
+	})
+	return instances, err
+}
+
+// parse populates the typed fields of VCenterInstance from its identifiers
+// and properties.
+func (m *VCenterInstance) parse(r *Resource) []error {
+	if r.Key != nil {
+		if id := r.Key.Identifier("VCURL"); id != nil {
+			m.VCURL = id.Value
+		}
+	}
+	if v := m.Properties["summary|version"]; v != "" {
+		m.Version = v
+	}
+	return nil
+}
+
+// ToJSONString serializes VCenterInstance to a string.
+func (m *VCenterInstance) ToJSONString() (string, error) {
+	itemJSON, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("failed converting to json: %s", err)
+	}
+	return string(itemJSON), nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported is returned when the version of the server does not
+// support the requested functionality.
+var ErrUnsupported = errors.New("unsupported by the server version")
+
+// Capability is a functionality available in some versions of the server.
+type Capability string
+
+// The capabilities of the server.
+const (
+	// CapabilityResourceQuery is the query of resources with complex
+	// criteria, e.g. tags, via resources/query.
+	CapabilityResourceQuery Capability = "resource_query"
+	// CapabilityBulkProperties is the retrieval of the properties of
+	// multiple resources in a single call via resources/properties.
+	CapabilityBulkProperties Capability = "bulk_properties"
+	// CapabilityCollectorGroups is the management of collector groups.
+	CapabilityCollectorGroups Capability = "collector_groups"
+	// CapabilityDTEnabled is the dtEnabled field of resources.
+	CapabilityDTEnabled Capability = "dt_enabled"
+)
+
+// capabilities are the minimum versions of the server, i.e. major and minor,
+// supporting the capabilities.
+var capabilities = map[Capability][2]int{
+	CapabilityResourceQuery:   {6, 3},
+	CapabilityBulkProperties:  {7, 0},
+	CapabilityCollectorGroups: {6, 6},
+	CapabilityDTEnabled:       {6, 3},
+}
+
+// Version is the version of vRealize Operations Manager, or Aria Operations.
+type Version struct {
+	// Name of the release, e.g. vRealize Operations Manager 8.1.0.
+	ReleaseName string `json:"releaseName,omitempty"`
+	// The major, minor and patch numbers of the release.
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+	Patch int `json:"minorMinor"`
+	// The build number of the release.
+	BuildNumber int `json:"buildNumber,omitempty"`
+	// The date of the release.
+	ReleasedDate Timestamp `json:"releasedDate"`
+}
+
+// GetVersion returns the version of the server. The version is retrieved
+// once and cached by Client.
+func (c *Client) GetVersion() (*Version, error) {
+	c.mu.Lock()
+	cached := c.version
+	c.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	b, err := c.request("GET", "versions/current", params)
+	if err != nil {
+		return nil, err
+	}
+	v := &Version{}
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling response: %s", err)
+	}
+	if v.Major == 0 {
+		return nil, fmt.Errorf("version not found in response: %s", string(b))
+	}
+	c.mu.Lock()
+	c.version = v
+	c.mu.Unlock()
+	return v, nil
+}
+
+// SetVersion sets the version of the server, instead of retrieving it.
+func (c *Client) SetVersion(v *Version) error {
+	if v == nil || v.Major == 0 {
+		return fmt.Errorf("invalid version")
+	}
+	c.mu.Lock()
+	c.version = v
+	c.mu.Unlock()
+	return nil
+}
+
+// Supports returns true when the server supports the capability.
+func (c *Client) Supports(capability Capability) (bool, error) {
+	v, err := c.GetVersion()
+	if err != nil {
+		return false, err
+	}
+	return v.Supports(capability), nil
+}
+
+// requireCapability returns ErrUnsupported when the server does not
+// support the capability.
+func (c *Client) requireCapability(capability Capability) error {
+	supported, err := c.Supports(capability)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return fmt.Errorf("%s: %w", capability, ErrUnsupported)
+	}
+	return nil
+}
+
+// AtLeast returns true when the version is the same or newer than
+// the provided major and minor version.
+func (v *Version) AtLeast(major, minor int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	return v.Minor >= minor
+}
+
+// Supports returns true when the version supports the capability.
+func (v *Version) Supports(capability Capability) bool {
+	required, exists := capabilities[capability]
+	if !exists {
+		return false
+	}
+	return v.AtLeast(required[0], required[1])
+}
+
+// String returns the version in major.minor.patch format.
+func (v *Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
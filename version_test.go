@@ -0,0 +1,95 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	"encoding/json"
+	"errors"
+	. "github.com/greenpau/go-vrop/internal/server"
+	"sync"
+	"testing"
+)
+
+func TestVersionSupports(t *testing.T) {
+	v := &Version{}
+	data := `{"releaseName":"vRealize Operations Manager 6.7.0","major":6,"minor":7,"minorMinor":0,"buildNumber":8183617,"releasedDate":1523491200000}`
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		t.Fatalf("failed unmarshalling version: %s", err)
+	}
+	if v.String() != "6.7.0" {
+		t.Fatalf("unexpected version: %s", v)
+	}
+
+	testcases := []struct {
+		capability Capability
+		supported  bool
+	}{
+		{capability: CapabilityResourceQuery, supported: true},
+		{capability: CapabilityCollectorGroups, supported: true},
+		{capability: CapabilityBulkProperties, supported: false},
+		{capability: Capability("unknown"), supported: false},
+	}
+	for _, tc := range testcases {
+		if v.Supports(tc.capability) != tc.supported {
+			t.Errorf("version %s, capability %s: expected supported %t", v, tc.capability, tc.supported)
+		}
+	}
+
+	cli, err := NewClient(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("failed initializing client: %s", err)
+	}
+	defer cli.Close()
+	if err := cli.SetVersion(v); err != nil {
+		t.Fatalf("failed setting version: %s", err)
+	}
+	if err := cli.requireCapability(CapabilityBulkProperties); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got: %v", err)
+	}
+	if err := cli.requireCapability(CapabilityResourceQuery); err != nil {
+		t.Fatalf("expected success, got: %s", err)
+	}
+}
+
+func TestGetVersionConcurrency(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/versions/current": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "versions_current.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			supported, err := cli.Supports(CapabilityBulkProperties)
+			if err != nil || !supported {
+				t.Errorf("unexpected support of %s: %t, %v", CapabilityBulkProperties, supported, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	v, err := cli.GetVersion()
+	if err != nil {
+		t.Fatalf("failed getting version: %s", err)
+	}
+	if v.String() != "8.1.0" {
+		t.Fatalf("unexpected version: %s", v)
+	}
+}
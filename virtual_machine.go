@@ -18,96 +18,68 @@ import (
 	"encoding/json"
 	"fmt"
 	//"go.uber.org/zap"
+	"sort"
 	"strconv"
-	"time"
+	"strings"
 )
 
-// VirtualMachineResourcesResponse is a response with VirtualMachine resources.
-type VirtualMachineResourcesResponse struct {
-	Page      *PageInfo   `json:"pageInfo,omitempty"`
-	Links     []*Link     `json:"links,omitempty"`
-	Resources []*Resource `json:"resourceList,omitempty"`
-}
+// VirtualMachineResourcesResponse is a response with resources.
+//
+// Deprecated: use ResourcesResponse, the response is not specific to
+// virtual machines.
+type VirtualMachineResourcesResponse = ResourcesResponse
 
 // VirtualMachine is a virtual machine.
 type VirtualMachine struct {
-	ID                         string            `json:"id,omitempty"`
-	Name                       string            `json:"name,omitempty"`
-	VMEntityInstanceUUID       string            `json:"vm_entity_instance_uuid,omitempty"`
-	VMEntityName               string            `json:"vm_entity_name,omitempty"`
-	VMEntityObjectID           string            `json:"vm_entity_object_id,omitempty"`
-	VMEntityVCID               string            `json:"vm_entity_vcid,omitempty"`
-	VMServiceMonitoringEnabled bool              `json:"vm_service_monitoring_enabled,omitempty"`
-	CreatedAt                  time.Time         `json:"created_at,omitempty"`
-	LastSeenAt                 time.Time         `json:"last_seen_at,omitempty"`
-	Properties                 map[string]string `json:"properties,omitempty"`
-	Errors                     []string          `json:"errors,omitempty"`
+	InventoryItem
+	VMEntityInstanceUUID       string   `json:"vm_entity_instance_uuid,omitempty"`
+	VMServiceMonitoringEnabled bool     `json:"vm_service_monitoring_enabled,omitempty"`
+	CPUCount                   int      `json:"cpu_count,omitempty"`
+	CoresPerSocket             int      `json:"cores_per_socket,omitempty"`
+	MemoryMB                   float64  `json:"memory_mb,omitempty"`
+	PowerState                 string   `json:"power_state,omitempty"`
+	GuestOS                    string   `json:"guest_os,omitempty"`
+	GuestHostName              string   `json:"guest_hostname,omitempty"`
+	IPAddresses                []string `json:"ip_addresses,omitempty"`
+	ToolsRunningStatus         string   `json:"tools_running_status,omitempty"`
+	ToolsVersionStatus         string   `json:"tools_version_status,omitempty"`
+	IsTemplate                 bool     `json:"is_template,omitempty"`
+	ParentHost                 string   `json:"parent_host,omitempty"`
+	ParentCluster              string   `json:"parent_cluster,omitempty"`
+	ParentDatacenter           string   `json:"parent_datacenter,omitempty"`
+	ParentVCenter              string   `json:"parent_vcenter,omitempty"`
+	Datastores                 []string `json:"datastores,omitempty"`
+	DiskSpaceGB                float64  `json:"disk_space_gb,omitempty"`
 }
 
-// GetVirtualMachines returns a list of VirtualMachine instances.
+// GetVirtualMachines returns a list of VirtualMachine instances, selected
+// by the options of GetResources, e.g. name or tags.
 func (c *Client) GetVirtualMachines(opts map[string]interface{}) ([]*VirtualMachine, error) {
 	machines := []*VirtualMachine{}
-	if err := c.authenticate(); err != nil {
-		return machines, err
-	}
-
-	pageOffset := 0
-	pageSize := 100
-
-	for {
-		params := make(map[string]string)
-		params["resourceKind"] = "virtualmachine"
-		params["page"] = strconv.Itoa(pageOffset)
-		params["pageSize"] = strconv.Itoa(pageSize)
-		b, err := c.request("GET", "resources", params)
-		if err != nil {
-			return machines, err
-		}
-
-		// return machines, fmt.Errorf(string(b))
-
-		resp := &VirtualMachineResourcesResponse{}
-		if err := json.Unmarshal(b, &resp); err != nil {
-			return machines, fmt.Errorf("failed unmarshalling response: %s", err)
-		}
+	err := c.getInventory("VMWARE", "VirtualMachine", opts, func() inventoryResource {
+		m := &VirtualMachine{}
+		machines = append(machines, m)
+		return m
+	})
+	return machines, err
+}
 
-		for _, r := range resp.Resources {
-			m := &VirtualMachine{}
-			m.Properties = make(map[string]string)
-			m.ID = r.ID
-			m.CreatedAt = r.CreationTime
-			m.LastSeenAt = time.Now().UTC()
-			m.Name = r.Key.Name
-			for _, entry := range r.Key.ResourceIdentifiers {
-				switch entry.Key {
-				case "VMEntityInstanceUUID":
-					m.VMEntityInstanceUUID = entry.Value
-				case "VMEntityName":
-					m.VMEntityName = entry.Value
-				case "VMEntityObjectID":
-					m.VMEntityObjectID = entry.Value
-				case "VMEntityVCID":
-					m.VMEntityVCID = entry.Value
-				case "VMServiceMonitoringEnabled":
-					if entry.Value == "true" || entry.Value == "True" || entry.Value == "TRUE" {
-						m.VMServiceMonitoringEnabled = true
-					}
+// parse populates the typed fields of VirtualMachine from the identifiers
+// and the properties of the resource.
+func (m *VirtualMachine) parse(r *Resource) []error {
+	if r.Key != nil {
+		for _, entry := range r.Key.ResourceIdentifiers {
+			switch entry.Key {
+			case "VMEntityInstanceUUID":
+				m.VMEntityInstanceUUID = entry.Value
+			case "VMServiceMonitoringEnabled":
+				if entry.Value == "true" || entry.Value == "True" || entry.Value == "TRUE" {
+					m.VMServiceMonitoringEnabled = true
 				}
 			}
-			if err := m.GetProperties(c); err != nil {
-				m.Errors = append(m.Errors, err.Error())
-
-			}
-			machines = append(machines, m)
-		}
-
-		if len(resp.Resources) < pageSize {
-			break
 		}
-		pageOffset++
 	}
-
-	return machines, nil
+	return m.parseProperties()
 }
 
 // ToJSONString serializes VirtualMachine to a string.
@@ -119,100 +91,99 @@ func (m *VirtualMachine) ToJSONString() (string, error) {
 	return string(itemJSON), nil
 }
 
-// UnmarshalJSON unpacks byte array into VirtualMachineResourcesResponse.
-func (c *VirtualMachineResourcesResponse) UnmarshalJSON(b []byte) error {
-	obj := "VirtualMachineResourcesResponse"
-	var requiredKeys = map[string]bool{
-		"resourceList": false,
-		"pageInfo":     false,
-		"links":        false,
-	}
-	var optionalKeys = map[string]bool{}
-	var m map[string]interface{}
-	if len(b) < 10 {
-		return fmt.Errorf("invalid %s data: %s", obj, b)
-	}
-	if err := json.Unmarshal(b, &m); err != nil {
-		return fmt.Errorf("failed to unpack %s", obj)
-	}
-
-	for k := range m {
-		if _, exists := requiredKeys[k]; exists {
-			requiredKeys[k] = true
-			continue
-		}
-		if _, exists := optionalKeys[k]; exists {
-			optionalKeys[k] = true
-			continue
-		}
-		return fmt.Errorf("failed to unpack %s, found unsupported key: %s", obj, k)
+// GetProperties fetches latest properties of VirtualMachine.
+func (m *VirtualMachine) GetProperties(c *Client) error {
+	properties, err := c.getResourceProperties(m.ID)
+	if m.Properties == nil {
+		m.Properties = make(map[string]string)
 	}
-
-	for k, present := range requiredKeys {
-		if !present {
-			return fmt.Errorf("failed to unpack %s, required key not found: %s", obj, k)
-		}
+	for k, v := range properties {
+		m.Properties[k] = v
 	}
-
-	p, err := unpackPageInfo(m["pageInfo"])
 	if err != nil {
-		return fmt.Errorf("failed to unpack %s pageInfo: %s", obj, err)
+		return fmt.Errorf("failed fetching VirtualMachine properties: %s", err)
 	}
-	c.Page = p
-
-	for _, item := range m["links"].([]interface{}) {
-		link, err := unpackLink(item)
-		if err != nil {
-			return fmt.Errorf("failed to unpack %s link: %s", obj, err)
-		}
-		c.Links = append(c.Links, link)
-	}
-
-	for _, item := range m["resourceList"].([]interface{}) {
-		resource, err := unpackResource(item)
-		if err != nil {
-			return fmt.Errorf("failed to unpack %s resourceList: %s", obj, err)
-		}
-		c.Resources = append(c.Resources, resource)
+	for _, err := range m.parseProperties() {
+		m.Errors = append(m.Errors, err.Error())
 	}
-
 	return nil
 }
 
-// GetProperties fetches latest properties of VirtualMachine.
-func (m *VirtualMachine) GetProperties(c *Client) error {
-	params := make(map[string]string)
-	b, err := c.request("GET", "resources/"+m.ID+"/properties", params)
-	if err != nil {
-		return err
-	}
-
-	resp := make(map[string]interface{})
-	if err := json.Unmarshal(b, &resp); err != nil {
-		return fmt.Errorf("failed unmarshalling VirtualMachine GetProperties response: %s", err)
-	}
-
-	if v, exists := resp["resourceId"]; exists {
-		if v.(string) != m.ID {
-			return fmt.Errorf("failed unmarshalling VirtualMachine GetProperties response: resourceId mismatch %s (expected) vs. %s (received)", m.ID, v.(string))
+// parseProperties populates the typed fields of VirtualMachine from
+// its properties. It returns the errors encountered during parsing.
+func (m *VirtualMachine) parseProperties() []error {
+	var errors []error
+	for k, v := range m.Properties {
+		if v == "" {
+			continue
 		}
-	} else {
-		return fmt.Errorf("failed unmarshalling VirtualMachine GetProperties response: resourceId not found")
-	}
-
-	if v, exists := resp["property"]; exists {
-		vm := v.([]interface{})
-		for _, vme := range vm {
-			entry := vme.(map[string]interface{})
-			if _, exists := entry["name"]; !exists {
-				continue
+		var err error
+		switch k {
+		case "config|hardware|numCpu":
+			m.CPUCount, err = strconv.Atoi(v)
+		case "config|hardware|numCoresPerSocket":
+			m.CoresPerSocket, err = strconv.Atoi(v)
+		case "config|hardware|memoryKB":
+			var kb float64
+			kb, err = strconv.ParseFloat(v, 64)
+			m.MemoryMB = kb / 1024
+		case "config|hardware|diskSpace":
+			m.DiskSpaceGB, err = strconv.ParseFloat(v, 64)
+		case "summary|runtime|powerState":
+			m.PowerState = v
+		case "config|guestFullName":
+			m.GuestOS = v
+		case "summary|guest|fullName":
+			if m.GuestOS == "" {
+				m.GuestOS = v
+			}
+		case "summary|guest|hostName":
+			m.GuestHostName = v
+		case "summary|guest|ipAddress":
+			m.addIPAddresses(v)
+		case "summary|guest|toolsRunningStatus":
+			m.ToolsRunningStatus = v
+		case "summary|guest|toolsVersionStatus2":
+			m.ToolsVersionStatus = v
+		case "summary|config|isTemplate":
+			m.IsTemplate, err = strconv.ParseBool(v)
+		case "summary|parentHost":
+			m.ParentHost = v
+		case "summary|parentCluster":
+			m.ParentCluster = v
+		case "summary|parentDatacenter":
+			m.ParentDatacenter = v
+		case "summary|parentVcenter":
+			m.ParentVCenter = v
+		case "summary|datastore":
+			m.Datastores = nil
+			for _, ds := range strings.Split(v, ",") {
+				if ds = strings.TrimSpace(ds); ds != "" {
+					m.Datastores = append(m.Datastores, ds)
+				}
 			}
-			if _, exists := entry["value"]; !exists {
-				continue
+		default:
+			if strings.HasPrefix(k, "net:") && strings.HasSuffix(k, "|ip_address") {
+				m.addIPAddresses(v)
 			}
-			m.Properties[entry["name"].(string)] = entry["value"].(string)
+		}
+		if err != nil {
+			errors = append(errors, fmt.Errorf("failed parsing VirtualMachine property %s: %s", k, err))
 		}
 	}
+	sort.Strings(m.IPAddresses)
+	sort.Strings(m.Datastores)
+	return errors
+}
 
-	return nil
+// addIPAddresses adds comma-separated IP addresses to VirtualMachine
+// while skipping duplicates.
+func (m *VirtualMachine) addIPAddresses(s string) {
+	for _, addr := range strings.Split(s, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" || containsString(m.IPAddresses, addr) {
+			continue
+		}
+		m.IPAddresses = append(m.IPAddresses, addr)
+	}
 }
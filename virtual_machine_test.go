@@ -0,0 +1,114 @@
+// Copyright 2020 Paul Greenberg greenpau@outlook.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrop
+
+import (
+	. "github.com/greenpau/go-vrop/internal/server"
+	"strings"
+	"testing"
+)
+
+func TestVirtualMachineParseProperties(t *testing.T) {
+	m := &VirtualMachine{}
+	m.Properties = map[string]string{
+		"config|hardware|numCpu":     "4",
+		"config|hardware|memoryKB":   "8388608",
+		"summary|runtime|powerState": "Powered On",
+		"config|guestFullName":       "CentOS 7 (64-bit)",
+		"summary|guest|ipAddress":    "10.0.0.10",
+		"net:4000|ip_address":        "10.0.0.10,10.0.1.10",
+		"summary|parentHost":         "esx01",
+		"summary|parentCluster":      "cluster01",
+		"summary|datastore":          "ds02, ds01",
+		"summary|config|isTemplate":  "yes",
+	}
+	errors := m.parseProperties()
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errors), errors)
+	}
+	if m.CPUCount != 4 {
+		t.Fatalf("unexpected cpu count: %d", m.CPUCount)
+	}
+	if m.MemoryMB != 8192 {
+		t.Fatalf("unexpected memory: %f", m.MemoryMB)
+	}
+	if m.PowerState != "Powered On" || m.GuestOS != "CentOS 7 (64-bit)" {
+		t.Fatalf("unexpected power state or guest os: %s, %s", m.PowerState, m.GuestOS)
+	}
+	if len(m.IPAddresses) != 2 || m.IPAddresses[0] != "10.0.0.10" || m.IPAddresses[1] != "10.0.1.10" {
+		t.Fatalf("unexpected ip addresses: %v", m.IPAddresses)
+	}
+	if m.ParentHost != "esx01" || m.ParentCluster != "cluster01" {
+		t.Fatalf("unexpected placement: %s, %s", m.ParentHost, m.ParentCluster)
+	}
+	if len(m.Datastores) != 2 || m.Datastores[0] != "ds01" {
+		t.Fatalf("unexpected datastores: %v", m.Datastores)
+	}
+}
+
+func TestGetVirtualMachines(t *testing.T) {
+	cli, server := newMockClient(t, map[string][]*MockTestEndpoint{
+		"/suite-api/api/versions/current": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "versions_current.json"},
+		},
+		"/suite-api/api/resources": []*MockTestEndpoint{
+			&MockTestEndpoint{FileName: "virtual_machines_sparse.json"},
+		},
+		"/suite-api/api/resources/properties": []*MockTestEndpoint{
+			&MockTestEndpoint{Method: "POST", FileName: "virtual_machines_properties.json"},
+		},
+	})
+	defer server.Close()
+	defer cli.Close()
+
+	machines, err := cli.GetVirtualMachines(map[string]interface{}{"name": "Server"})
+	if err != nil {
+		t.Fatalf("failed getting virtual machines: %s", err)
+	}
+	if len(machines) != 2 {
+		t.Fatalf("expected 2 virtual machines, got %d", len(machines))
+	}
+	m := machines[0]
+	if m.Name != "Server1" || m.VMEntityObjectID != "vm-121" || m.VMEntityInstanceUUID == "" {
+		t.Fatalf("unexpected virtual machine identifiers: %+v", m)
+	}
+	if m.CPUCount != 2 || m.MemoryMB != 4096 || m.ParentHost != "esx01.example.com" {
+		t.Fatalf("unexpected virtual machine properties: %+v", m)
+	}
+	// The resource without a resource key is listed, rather than panic.
+	if m := machines[1]; m.Name != "" || m.PowerState != "Powered Off" || len(m.Errors) != 0 {
+		t.Fatalf("unexpected virtual machine without resource key: %+v", m)
+	}
+
+	// The options are applied to the listing, and the properties are
+	// retrieved in a single call.
+	var listings, propertyCalls int
+	for _, req := range server.Requests() {
+		switch {
+		case strings.HasPrefix(req.RequestURI, "/suite-api/api/resources?"):
+			listings++
+			for _, param := range []string{"adapterKind=VMWARE", "resourceKind=VirtualMachine", "name=Server"} {
+				if !strings.Contains(req.RequestURI, param) {
+					t.Fatalf("expected %s in request %s", param, req.RequestURI)
+				}
+			}
+		case strings.HasPrefix(req.RequestURI, "/suite-api/api/resources/"):
+			propertyCalls++
+		}
+	}
+	if listings != 1 || propertyCalls != 1 {
+		t.Fatalf("expected 1 listing and 1 properties call, got %d and %d", listings, propertyCalls)
+	}
+}